@@ -0,0 +1,166 @@
+// Package datagen generates synthetic labeled datasets with known ground
+// truth, useful to test learning algorithms without depending on files.
+//
+// Every generator returns a matrix with a row per example, with the features
+// in the first columns and the target in the last one. This is the layout of
+// the files read with util.ParseMatrix and expected by linreg.InitParameters.
+// The same seed always generates the same dataset.
+package datagen
+
+import (
+	"math"
+	"math/rand"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+)
+
+// Blobs generates n points around each of the given centers, drawn from
+// a normal distribution with the given standard deviation. The target of
+// each point is the index of its center.
+func Blobs(n int, centers [][]float64, std float64, seed int64) *mat.Dense {
+	rng := rand.New(rand.NewSource(seed))
+	dims := len(centers[0])
+	data := mat.NewDense(n*len(centers), dims+1, nil)
+	for c, center := range centers {
+		for i := 0; i < n; i++ {
+			row := data.RawRowView(c*n + i)
+			for j, v := range center {
+				row[j] = v + std*rng.NormFloat64()
+			}
+			row[dims] = float64(c)
+		}
+	}
+	return data
+}
+
+// Moons generates two interleaving half circles of n points each, with
+// normal noise of the given standard deviation added to the coordinates.
+// The upper moon has target 0 and the lower one target 1.
+func Moons(n int, noise float64, seed int64) *mat.Dense {
+	rng := rand.New(rand.NewSource(seed))
+	data := mat.NewDense(2*n, 3, nil)
+	for i := 0; i < n; i++ {
+		t := math.Pi * float64(i) / float64(max(n-1, 1))
+		data.SetRow(i, []float64{
+			math.Cos(t) + noise*rng.NormFloat64(),
+			math.Sin(t) + noise*rng.NormFloat64(),
+			0,
+		})
+		data.SetRow(n+i, []float64{
+			1 - math.Cos(t) + noise*rng.NormFloat64(),
+			0.5 - math.Sin(t) + noise*rng.NormFloat64(),
+			1,
+		})
+	}
+	return data
+}
+
+// Circles generates two concentric circles of n points each. The outer one
+// has radius 1 and target 0, the inner one has radius factor and target 1.
+// Normal noise of the given standard deviation is added to the coordinates.
+func Circles(n int, factor, noise float64, seed int64) *mat.Dense {
+	rng := rand.New(rand.NewSource(seed))
+	data := mat.NewDense(2*n, 3, nil)
+	for i := 0; i < n; i++ {
+		t := 2 * math.Pi * float64(i) / float64(n)
+		data.SetRow(i, []float64{
+			math.Cos(t) + noise*rng.NormFloat64(),
+			math.Sin(t) + noise*rng.NormFloat64(),
+			0,
+		})
+		data.SetRow(n+i, []float64{
+			factor*math.Cos(t) + noise*rng.NormFloat64(),
+			factor*math.Sin(t) + noise*rng.NormFloat64(),
+			1,
+		})
+	}
+	return data
+}
+
+// XOR generates n points drawn uniformly from the square [-1, 1]x[-1, 1],
+// with target 1 when the signs of their coordinates differ and 0 otherwise.
+// Normal noise of the given standard deviation is added to the coordinates
+// after computing the target.
+func XOR(n int, noise float64, seed int64) *mat.Dense {
+	rng := rand.New(rand.NewSource(seed))
+	data := mat.NewDense(n, 3, nil)
+	for i := 0; i < n; i++ {
+		x, y := 2*rng.Float64()-1, 2*rng.Float64()-1
+		label := 0.0
+		if (x < 0) != (y < 0) {
+			label = 1
+		}
+		data.SetRow(i, []float64{x + noise*rng.NormFloat64(), y + noise*rng.NormFloat64(), label})
+	}
+	return data
+}
+
+// Linear generates n examples with len(theta)-1 features drawn from a
+// standard normal distribution, and target theta[0] + theta[1]*x1 + ...,
+// so theta has the shape returned by linreg.InitParameters.
+//
+// Normal noise of the given standard deviation is added to the target, and
+// a fraction of the examples given by outliers get an additional error of
+// between 5 and 10 times the standard deviation of the clean targets. The
+// fraction is clamped to [0, 1], and a NaN fraction adds no outliers.
+func Linear(n int, theta []float64, noise, outliers float64, seed int64) *mat.Dense {
+	rng := rand.New(rand.NewSource(seed))
+	k := len(theta) - 1
+	data := mat.NewDense(n, k+1, nil)
+	ys := make([]float64, n)
+	for i := 0; i < n; i++ {
+		row := data.RawRowView(i)
+		ys[i] = theta[0]
+		for j := 0; j < k; j++ {
+			row[j] = rng.NormFloat64()
+			ys[i] += theta[j+1] * row[j]
+		}
+	}
+	addNoise(rng, ys, noise, outliers)
+	data.SetCol(k, ys)
+	return data
+}
+
+// Polynomial generates n examples with a single feature x drawn uniformly
+// from [-1, 1] and target coefs[0] + coefs[1]*x + coefs[2]*x^2 + ...
+// Noise and outliers are added to the target as described for Linear.
+func Polynomial(n int, coefs []float64, noise, outliers float64, seed int64) *mat.Dense {
+	rng := rand.New(rand.NewSource(seed))
+	data := mat.NewDense(n, 2, nil)
+	ys := make([]float64, n)
+	for i := 0; i < n; i++ {
+		x := 2*rng.Float64() - 1
+		for k := len(coefs) - 1; k >= 0; k-- {
+			ys[i] = ys[i]*x + coefs[k]
+		}
+		data.Set(i, 0, x)
+	}
+	addNoise(rng, ys, noise, outliers)
+	data.SetCol(1, ys)
+	return data
+}
+
+// addNoise adds normal noise and outliers to ys, as described for Linear.
+func addNoise(rng *rand.Rand, ys []float64, noise, outliers float64) {
+	scale := 1.0
+	if len(ys) > 1 {
+		if std := stat.StdDev(ys, nil); std > 0 {
+			scale = std
+		}
+	}
+	for i := range ys {
+		ys[i] += noise * rng.NormFloat64()
+	}
+	if !(outliers > 0) {
+		outliers = 0
+	}
+	outliers = math.Min(outliers, 1)
+	for _, i := range rng.Perm(len(ys))[:int(outliers*float64(len(ys)))] {
+		err := (5 + 5*rng.Float64()) * scale
+		if rng.Intn(2) == 0 {
+			err = -err
+		}
+		ys[i] += err
+	}
+}
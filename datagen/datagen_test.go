@@ -0,0 +1,118 @@
+package datagen
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+
+	"github.com/campoy/goml/linreg"
+)
+
+func TestShapes(t *testing.T) {
+	tc := []struct {
+		name       string
+		data       *mat.Dense
+		rows, cols int
+		labels     int
+	}{
+		{"blobs", Blobs(10, [][]float64{{0, 0}, {5, 5}, {-5, 5}}, 1, 1), 30, 3, 3},
+		{"moons", Moons(20, 0.1, 1), 40, 3, 2},
+		{"circles", Circles(20, 0.5, 0.1, 1), 40, 3, 2},
+		{"xor", XOR(50, 0, 1), 50, 3, 2},
+		{"linear", Linear(20, []float64{1, 2, 3, 4}, 0.1, 0, 1), 20, 4, 0},
+		{"polynomial", Polynomial(20, []float64{1, 0, 2}, 0.1, 0, 1), 20, 2, 0},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			r, c := tt.data.Dims()
+			if r != tt.rows || c != tt.cols {
+				t.Fatalf("expected %dx%d matrix; got %dx%d", tt.rows, tt.cols, r, c)
+			}
+			if tt.labels == 0 {
+				return
+			}
+			seen := make(map[float64]bool)
+			for _, l := range mat.Col(nil, c-1, tt.data) {
+				seen[l] = true
+			}
+			if len(seen) != tt.labels {
+				t.Errorf("expected %d labels; got %v", tt.labels, seen)
+			}
+		})
+	}
+}
+
+func TestSeed(t *testing.T) {
+	if !mat.Equal(Moons(10, 0.2, 7), Moons(10, 0.2, 7)) {
+		t.Errorf("expected the same seed to generate the same data")
+	}
+	if mat.Equal(Moons(10, 0.2, 7), Moons(10, 0.2, 8)) {
+		t.Errorf("expected different seeds to generate different data")
+	}
+}
+
+func TestXOR(t *testing.T) {
+	data := XOR(100, 0, 1)
+	for i := 0; i < 100; i++ {
+		x, y, l := data.At(i, 0), data.At(i, 1), data.At(i, 2)
+		if (x*y < 0) != (l == 1) {
+			t.Errorf("point (%v, %v) has wrong label %v", x, y, l)
+		}
+	}
+}
+
+func TestLinearGroundTruth(t *testing.T) {
+	theta := []float64{1, 2, -3}
+	X, y, got := linreg.InitParameters(Linear(200, theta, 0.01, 0, 1))
+	got, _, _ = linreg.GradientDescent(X, y, got, 0.1, 1000)
+	for i, want := range theta {
+		if math.Abs(got.At(i, 0)-want) > 0.01 {
+			t.Errorf("expected theta[%d] to be close to %v; got %v", i, want, got.At(i, 0))
+		}
+	}
+}
+
+func TestPolynomial(t *testing.T) {
+	data := Polynomial(10, []float64{1, -2, 3}, 0, 0, 1)
+	for i := 0; i < 10; i++ {
+		x := data.At(i, 0)
+		if want, got := 1-2*x+3*x*x, data.At(i, 1); math.Abs(want-got) > 1e-12 {
+			t.Errorf("expected y(%v) to be %v; got %v", x, want, got)
+		}
+	}
+}
+
+func TestOutliers(t *testing.T) {
+	clean := Linear(100, []float64{0, 1}, 0, 0, 1)
+	dirty := Linear(100, []float64{0, 1}, 0, 0.1, 1)
+	changed := 0
+	for i := 0; i < 100; i++ {
+		if clean.At(i, 1) != dirty.At(i, 1) {
+			changed++
+		}
+	}
+	if changed != 10 {
+		t.Errorf("expected 10 outliers; got %d", changed)
+	}
+
+	// Fractions out of [0, 1] are clamped.
+	tc := []struct {
+		outliers float64
+		want     int
+	}{{-0.5, 0}, {math.NaN(), 0}, {2, 100}}
+	for _, tt := range tc {
+		outliers, want := tt.outliers, tt.want
+		dirty := Linear(100, []float64{0, 1}, 0, outliers, 1)
+		changed := 0
+		for i := 0; i < 100; i++ {
+			if clean.At(i, 1) != dirty.At(i, 1) {
+				changed++
+			}
+		}
+		if changed != want {
+			t.Errorf("expected %d outliers for fraction %v; got %d", want, outliers, changed)
+		}
+	}
+}
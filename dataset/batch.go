@@ -0,0 +1,164 @@
+package dataset
+
+import (
+	"math/rand"
+
+	"github.com/pkg/errors"
+	"gonum.org/v1/gonum/mat"
+)
+
+// A RowSource provides the features and targets of examples one at a time,
+// so batches can be built without materializing the whole dataset.
+type RowSource interface {
+	// Len returns the number of examples.
+	Len() int
+	// Dims returns the number of features and targets of each example.
+	Dims() (x, y int)
+	// Row copies the features and targets of the ith example into x and y.
+	Row(i int, x, y []float64)
+}
+
+// DenseSource returns a RowSource reading the rows of X and Y. A nil X or Y
+// is a source without examples or without targets, which NewBatches rejects.
+func DenseSource(X, Y *mat.Dense) RowSource { return denseSource{X, Y} }
+
+type denseSource struct{ x, y *mat.Dense }
+
+func (s denseSource) Len() int {
+	if s.x == nil {
+		return 0
+	}
+	r, _ := s.x.Dims()
+	return r
+}
+
+func (s denseSource) Dims() (x, y int) {
+	if s.x != nil {
+		_, x = s.x.Dims()
+	}
+	if s.y != nil {
+		_, y = s.y.Dims()
+	}
+	return x, y
+}
+
+func (s denseSource) Row(i int, x, y []float64) {
+	copy(x, s.x.RawRowView(i))
+	copy(y, s.y.RawRowView(i))
+}
+
+// BatchOptions configures how examples are grouped in batches.
+type BatchOptions struct {
+	// Shuffle changes the order of the examples on every epoch.
+	Shuffle bool
+	// Seed seeds the generator that shuffles every epoch, so the same
+	// seed always produces the same sequence of batches.
+	Seed int64
+	// DropLast skips the last batch of an epoch when it is not full.
+	DropLast bool
+}
+
+// Batches iterates over the examples of a RowSource in mini-batches.
+//
+//	b, err := dataset.NewBatches(src, 32, dataset.BatchOptions{Shuffle: true})
+//	if err != nil { ... }
+//	for epoch := 0; epoch < 10; epoch++ {
+//		for b.Next() {
+//			update(b.X(), b.Y())
+//		}
+//		b.Reset()
+//	}
+type Batches struct {
+	src   RowSource
+	size  int
+	opts  BatchOptions
+	rng   *rand.Rand
+	epoch int
+	order []int
+	pos   int
+	idx   []int
+
+	xBuf, yBuf *mat.Dense
+	x, y       *mat.Dense
+}
+
+// NewBatches returns an iterator over batches of the given size from src.
+// It fails unless src has examples with both features and targets.
+func NewBatches(src RowSource, size int, opts BatchOptions) (*Batches, error) {
+	if size <= 0 {
+		return nil, errors.Errorf("batch size must be positive, got %d", size)
+	}
+	if src.Len() == 0 {
+		return nil, errors.New("source has no examples")
+	}
+	xc, yc := src.Dims()
+	if xc == 0 || yc == 0 {
+		return nil, errors.Errorf("examples need features and targets, got %d and %d", xc, yc)
+	}
+	b := &Batches{
+		src:   src,
+		size:  size,
+		opts:  opts,
+		rng:   rand.New(rand.NewSource(opts.Seed)),
+		order: make([]int, src.Len()),
+		xBuf:  mat.NewDense(size, xc, nil),
+		yBuf:  mat.NewDense(size, yc, nil),
+	}
+	b.shuffle()
+	return b, nil
+}
+
+// Epoch returns the number of times Reset has been called.
+func (b *Batches) Epoch() int { return b.epoch }
+
+// Reset starts a new epoch, shuffling the examples again if requested.
+func (b *Batches) Reset() {
+	b.epoch++
+	b.pos = 0
+	b.shuffle()
+}
+
+func (b *Batches) shuffle() {
+	for i := range b.order {
+		b.order[i] = i
+	}
+	if b.opts.Shuffle {
+		b.rng.Shuffle(len(b.order), func(i, j int) { b.order[i], b.order[j] = b.order[j], b.order[i] })
+	}
+}
+
+// Next prepares the next batch of the epoch, and reports whether there was one.
+func (b *Batches) Next() bool {
+	n := len(b.order) - b.pos
+	if n > b.size {
+		n = b.size
+	}
+	if n == 0 || (n < b.size && b.opts.DropLast) {
+		return false
+	}
+
+	b.idx = b.order[b.pos : b.pos+n]
+	b.pos += n
+	for i, row := range b.idx {
+		b.src.Row(row, b.xBuf.RawRowView(i), b.yBuf.RawRowView(i))
+	}
+
+	b.x, b.y = b.xBuf, b.yBuf
+	if n < b.size {
+		xc, yc := b.src.Dims()
+		b.x = b.xBuf.Slice(0, n, 0, xc).(*mat.Dense)
+		b.y = b.yBuf.Slice(0, n, 0, yc).(*mat.Dense)
+	}
+	return true
+}
+
+// X returns the features of the current batch.
+// Its contents are overwritten by the next call to Next.
+func (b *Batches) X() *mat.Dense { return b.x }
+
+// Y returns the targets of the current batch.
+// Its contents are overwritten by the next call to Next.
+func (b *Batches) Y() *mat.Dense { return b.y }
+
+// Index returns the indices in the source of the examples in the current batch.
+func (b *Batches) Index() []int { return b.idx }
@@ -0,0 +1,100 @@
+package dataset
+
+import (
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestBatches(t *testing.T) {
+	X := mat.NewDense(10, 1, []float64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9})
+	Y := mat.NewDense(10, 1, []float64{0, 10, 20, 30, 40, 50, 60, 70, 80, 90})
+
+	tc := []struct {
+		name  string
+		opts  BatchOptions
+		sizes []int
+	}{
+		{"in order", BatchOptions{}, []int{4, 4, 2}},
+		{"shuffled", BatchOptions{Shuffle: true, Seed: 3}, []int{4, 4, 2}},
+		{"drop last", BatchOptions{DropLast: true}, []int{4, 4}},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := NewBatches(DenseSource(X, Y), 4, tt.opts)
+			if err != nil {
+				t.Fatalf("could not create batches: %v", err)
+			}
+			var epochs [][]int
+			for epoch := 0; epoch < 2; epoch++ {
+				var sizes, order []int
+				for b.Next() {
+					r, _ := b.X().Dims()
+					sizes = append(sizes, r)
+					for i, idx := range b.Index() {
+						if x, y := b.X().At(i, 0), b.Y().At(i, 0); x != float64(idx) || y != 10*x {
+							t.Fatalf("batch row %d does not match example %d: x=%v y=%v", i, idx, x, y)
+						}
+						order = append(order, idx)
+					}
+				}
+				if len(sizes) != len(tt.sizes) {
+					t.Fatalf("expected batch sizes %v; got %v", tt.sizes, sizes)
+				}
+				for i := range sizes {
+					if sizes[i] != tt.sizes[i] {
+						t.Fatalf("expected batch sizes %v; got %v", tt.sizes, sizes)
+					}
+				}
+				epochs = append(epochs, order)
+				b.Reset()
+			}
+
+			same := true
+			for i := range epochs[0] {
+				same = same && epochs[0][i] == epochs[1][i]
+			}
+			if same == tt.opts.Shuffle {
+				t.Errorf("expected epochs to be reshuffled only when Shuffle is set; got %v", epochs)
+			}
+		})
+	}
+}
+
+func TestBatchesSeeds(t *testing.T) {
+	X := mat.NewDense(20, 1, nil)
+	src := DenseSource(X, X)
+
+	// order returns the order of the examples in the given epoch.
+	order := func(seed int64, epoch int) []int {
+		b, err := NewBatches(src, 20, BatchOptions{Shuffle: true, Seed: seed})
+		if err != nil {
+			t.Fatalf("could not create batches: %v", err)
+		}
+		for i := 0; i < epoch; i++ {
+			b.Reset()
+		}
+		b.Next()
+		return append([]int(nil), b.Index()...)
+	}
+
+	a, b := order(1, 1), order(2, 0)
+	same := true
+	for i := range a {
+		same = same && a[i] == b[i]
+	}
+	if same {
+		t.Errorf("expected seed 1 epoch 1 to differ from seed 2 epoch 0; got %v", a)
+	}
+
+	if _, err := NewBatches(src, 0, BatchOptions{}); err == nil {
+		t.Errorf("expected error for batch size 0")
+	}
+	if _, err := NewBatches(DenseSource(X, nil), 4, BatchOptions{}); err == nil {
+		t.Errorf("expected error for a source without targets")
+	}
+	if _, err := NewBatches(DenseSource(nil, nil), 4, BatchOptions{}); err == nil {
+		t.Errorf("expected error for a source without examples")
+	}
+}
@@ -0,0 +1,163 @@
+package dataset
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/pkg/errors"
+	"gonum.org/v1/gonum/stat"
+)
+
+// A Fold contains the indices of the examples used to train and to score
+// a model in one round of cross-validation.
+type Fold struct {
+	Train, Test []int
+}
+
+// KFold splits n shuffled examples into k folds of almost equal size, each
+// used once as the test set while the rest are used for training.
+// It fails unless k is between 2 and n.
+func KFold(n, k int, seed int64) ([]Fold, error) {
+	if err := checkFolds(n, k); err != nil {
+		return nil, err
+	}
+	return folds(n, k, rand.New(rand.NewSource(seed)).Perm(n)), nil
+}
+
+// StratifiedKFold works like KFold, but keeps the proportion of each class
+// in labels the same in every fold.
+func StratifiedKFold(labels []float64, k int, seed int64) ([]Fold, error) {
+	if err := checkFolds(len(labels), k); err != nil {
+		return nil, err
+	}
+	rng := rand.New(rand.NewSource(seed))
+
+	// Dealing the shuffled examples of each class in turn keeps the
+	// classes evenly spread over the folds.
+	var order []int
+	for _, idx := range classes(labels) {
+		rng.Shuffle(len(idx), func(i, j int) { idx[i], idx[j] = idx[j], idx[i] })
+		order = append(order, idx...)
+	}
+
+	test := make([][]int, k)
+	for i, v := range order {
+		test[i%k] = append(test[i%k], v)
+	}
+	return fromTestSets(len(labels), test), nil
+}
+
+// LeaveOneOut returns n folds, each using a single example as the test set.
+// It fails unless there are at least 2 examples.
+func LeaveOneOut(n int) ([]Fold, error) {
+	if err := checkFolds(n, n); err != nil {
+		return nil, err
+	}
+	perm := make([]int, n)
+	for i := range perm {
+		perm[i] = i
+	}
+	return folds(n, n, perm), nil
+}
+
+// RepeatedKFold returns the folds of repeating KFold the given number of
+// times, each with a different shuffle derived from seed.
+// It fails unless repeats is positive.
+func RepeatedKFold(n, k, repeats int, seed int64) ([]Fold, error) {
+	if repeats <= 0 {
+		return nil, errors.Errorf("repeats must be positive, got %d", repeats)
+	}
+	var res []Fold
+	for r := 0; r < repeats; r++ {
+		f, err := KFold(n, k, seed+int64(r))
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, f...)
+	}
+	return res, nil
+}
+
+// folds splits the permutation perm of n examples into k folds.
+func folds(n, k int, perm []int) []Fold {
+	test := make([][]int, k)
+	start := 0
+	for i := range test {
+		size := n / k
+		if i < n%k {
+			size++
+		}
+		test[i] = perm[start : start+size]
+		start += size
+	}
+	return fromTestSets(n, test)
+}
+
+// fromTestSets returns a fold per test set, training on the rest of n examples.
+func fromTestSets(n int, test [][]int) []Fold {
+	res := make([]Fold, len(test))
+	for i, t := range test {
+		in := make([]bool, n)
+		for _, v := range t {
+			in[v] = true
+		}
+		train := make([]int, 0, n-len(t))
+		for v := 0; v < n; v++ {
+			if !in[v] {
+				train = append(train, v)
+			}
+		}
+		res[i] = Fold{Train: train, Test: t}
+	}
+	return res
+}
+
+// checkFolds returns an error unless n examples can be split into k folds.
+func checkFolds(n, k int) error {
+	if n == 0 {
+		return errors.New("no examples to split into folds")
+	}
+	if k < 2 || k > n {
+		return errors.Errorf("cannot split %d examples into %d folds", n, k)
+	}
+	return nil
+}
+
+// Scores contains the result of cross-validation.
+type Scores struct {
+	Folds []float64 // The score obtained on each fold.
+	Mean  float64
+	Std   float64
+}
+
+func (s Scores) String() string {
+	return fmt.Sprintf("%f ± %f over %d folds", s.Mean, s.Std, len(s.Folds))
+}
+
+// CrossValidate fits a model on the training examples of each fold and
+// scores it on its test examples. Both functions receive row indices, which
+// can be turned into matrices with Rows or MatrixRows.
+func CrossValidate[M any](folds []Fold,
+	fit func(train []int) (M, error),
+	score func(model M, test []int) (float64, error)) (Scores, error) {
+	var s Scores
+	if len(folds) == 0 {
+		return s, errors.New("no folds to cross validate")
+	}
+	for i, f := range folds {
+		model, err := fit(f.Train)
+		if err != nil {
+			return s, errors.Wrapf(err, "could not fit fold %d", i)
+		}
+		v, err := score(model, f.Test)
+		if err != nil {
+			return s, errors.Wrapf(err, "could not score fold %d", i)
+		}
+		s.Folds = append(s.Folds, v)
+	}
+	s.Mean = stat.Mean(s.Folds, nil)
+	if len(s.Folds) > 1 {
+		s.Std = stat.StdDev(s.Folds, nil)
+	}
+	return s, nil
+}
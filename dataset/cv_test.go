@@ -0,0 +1,128 @@
+package dataset
+
+import (
+	"sort"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+
+	"github.com/campoy/goml/linreg"
+)
+
+func TestFolds(t *testing.T) {
+	labels := []float64{0, 0, 0, 0, 0, 0, 1, 1, 1, 1}
+
+	tc := []struct {
+		name  string
+		folds func() ([]Fold, error)
+		n     int
+		count int
+	}{
+		{"k-fold", func() ([]Fold, error) { return KFold(10, 3, 1) }, 10, 3},
+		{"stratified k-fold", func() ([]Fold, error) { return StratifiedKFold(labels, 2, 1) }, 10, 2},
+		{"leave one out", func() ([]Fold, error) { return LeaveOneOut(5) }, 5, 5},
+		{"repeated k-fold", func() ([]Fold, error) { return RepeatedKFold(10, 5, 2, 1) }, 10, 10},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			folds, err := tt.folds()
+			if err != nil {
+				t.Fatalf("could not split: %v", err)
+			}
+			if len(folds) != tt.count {
+				t.Fatalf("expected %d folds; got %d", tt.count, len(folds))
+			}
+			for i, f := range folds {
+				all := append(append([]int{}, f.Train...), f.Test...)
+				sort.Ints(all)
+				if len(all) != tt.n {
+					t.Fatalf("fold %d: expected %d examples; got %d", i, tt.n, len(all))
+				}
+				for j, v := range all {
+					if j != v {
+						t.Fatalf("fold %d: expected every example once; got %v", i, all)
+					}
+				}
+			}
+		})
+	}
+
+	stratified, err := StratifiedKFold(labels, 2, 1)
+	if err != nil {
+		t.Fatalf("could not split: %v", err)
+	}
+	for i, f := range stratified {
+		pos := 0
+		for _, v := range f.Test {
+			pos += int(labels[v])
+		}
+		if pos != 2 {
+			t.Errorf("fold %d: expected 2 positive test examples; got %d", i, pos)
+		}
+	}
+}
+
+func TestFoldsErrors(t *testing.T) {
+	tc := []struct {
+		name  string
+		folds func() ([]Fold, error)
+	}{
+		{"one fold", func() ([]Fold, error) { return KFold(10, 1, 1) }},
+		{"more folds than examples", func() ([]Fold, error) { return KFold(3, 4, 1) }},
+		{"no examples", func() ([]Fold, error) { return KFold(0, 2, 1) }},
+		{"stratified without labels", func() ([]Fold, error) { return StratifiedKFold(nil, 2, 1) }},
+		{"leave one out of none", func() ([]Fold, error) { return LeaveOneOut(0) }},
+		{"leave one out of one", func() ([]Fold, error) { return LeaveOneOut(1) }},
+		{"no repeats", func() ([]Fold, error) { return RepeatedKFold(10, 5, 0, 1) }},
+		{"negative repeats", func() ([]Fold, error) { return RepeatedKFold(10, 5, -1, 1) }},
+	}
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			if folds, err := tt.folds(); err == nil {
+				t.Errorf("expected error; got %d folds", len(folds))
+			}
+		})
+	}
+
+	fit := func(train []int) (int, error) { return 0, nil }
+	score := func(int, []int) (float64, error) { return 0, nil }
+	if _, err := CrossValidate(nil, fit, score); err == nil {
+		t.Errorf("expected error cross validating without folds")
+	}
+}
+
+func TestCrossValidate(t *testing.T) {
+	// y = 1 + 2x, which gradient descent should fit with almost no error.
+	X := mat.NewDense(20, 2, nil)
+	y := mat.NewDense(20, 1, nil)
+	for i := 0; i < 20; i++ {
+		x := float64(i) / 10
+		X.Set(i, 0, 1)
+		X.Set(i, 1, x)
+		y.Set(i, 0, 1+2*x)
+	}
+
+	fit := func(train []int) (*mat.Dense, error) {
+		theta, _, _ := linreg.GradientDescent(Rows(X, train), Rows(y, train), mat.NewDense(2, 1, nil), 0.1, 2000)
+		return theta, nil
+	}
+	score := func(theta *mat.Dense, test []int) (float64, error) {
+		return linreg.ComputeCost(Rows(X, test), Rows(y, test), theta), nil
+	}
+
+	folds, err := KFold(20, 4, 1)
+	if err != nil {
+		t.Fatalf("could not split: %v", err)
+	}
+	s, err := CrossValidate(folds, fit, score)
+	if err != nil {
+		t.Fatalf("could not cross validate: %v", err)
+	}
+	if len(s.Folds) != 4 {
+		t.Fatalf("expected 4 scores; got %d", len(s.Folds))
+	}
+	if s.Mean > 1e-4 {
+		t.Errorf("expected a mean cost close to zero; got %s", s)
+	}
+}
@@ -0,0 +1,208 @@
+// Package dataset provides a container for features and targets, together
+// with reproducible ways of splitting it for training and evaluation.
+package dataset
+
+import (
+	"io"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+
+	cmat "github.com/campoy/mat"
+	"github.com/pkg/errors"
+	"gonum.org/v1/gonum/mat"
+
+	"github.com/campoy/goml/util"
+)
+
+// A Dataset contains the features X and targets Y of a set of examples,
+// one per row, and the names of the columns of X.
+type Dataset struct {
+	X     *mat.Dense
+	Y     *mat.Dense
+	Names []string
+}
+
+// FromTable returns a Dataset with a copy of the contents of the given table.
+func FromTable(t *util.Table) *Dataset {
+	d := &Dataset{X: util.Dense(t.X), Names: append([]string(nil), t.Names...)}
+	if t.Y.Cols() > 0 {
+		d.Y = util.Dense(t.Y)
+	}
+	return d
+}
+
+// Len returns the number of examples in the dataset.
+func (d *Dataset) Len() int {
+	r, _ := d.X.Dims()
+	return r
+}
+
+// Subset returns a new dataset with a copy of the examples with the given indices.
+func (d *Dataset) Subset(idx []int) *Dataset {
+	s := &Dataset{X: Rows(d.X, idx), Names: d.Names}
+	if d.Y != nil {
+		s.Y = Rows(d.Y, idx)
+	}
+	return s
+}
+
+// Split returns the train, validation and test subsets defined by s.
+// Subsets without examples are returned as nil.
+func (d *Dataset) Split(s Split) (train, validation, test *Dataset) {
+	sub := func(idx []int) *Dataset {
+		if len(idx) == 0 {
+			return nil
+		}
+		return d.Subset(idx)
+	}
+	return sub(s.Train), sub(s.Validation), sub(s.Test)
+}
+
+// Labels returns the class of each example, which is the value of Y when it
+// has a single column, or the position of its largest value when it is hot
+// encoded. It returns nil when the dataset has no targets.
+func (d *Dataset) Labels() []float64 {
+	if d.Y == nil {
+		return nil
+	}
+	r, c := d.Y.Dims()
+	labels := make([]float64, r)
+	for i := range labels {
+		if c == 1 {
+			labels[i] = d.Y.At(i, 0)
+			continue
+		}
+		best := math.Inf(-1)
+		for j := 0; j < c; j++ {
+			if v := d.Y.At(i, j); v > best {
+				best, labels[i] = v, float64(j)
+			}
+		}
+	}
+	return labels
+}
+
+// WriteCSV writes the examples to w with the columns of X followed by those
+// of Y, see util.WriteCSV. The header uses Names for X, and y, or y0, y1...
+// when Y has many columns, but it is omitted when Names is empty.
+func (d *Dataset) WriteCSV(w io.Writer, prec int) error {
+	var m util.Matrix = d.X
+	names := d.Names
+	if d.Y != nil {
+		xy := new(mat.Dense)
+		xy.Augment(d.X, d.Y)
+		m = xy
+		if len(names) > 0 {
+			names = append([]string(nil), names...)
+			_, k := d.Y.Dims()
+			for j := 0; j < k; j++ {
+				if k == 1 {
+					names = append(names, "y")
+				} else {
+					names = append(names, "y"+strconv.Itoa(j))
+				}
+			}
+		}
+	}
+	return util.WriteCSV(w, m, names, prec)
+}
+
+// Rows returns a copy of the rows of m with the given indices.
+func Rows(m mat.Matrix, idx []int) *mat.Dense {
+	_, c := m.Dims()
+	res := mat.NewDense(len(idx), c, nil)
+	for i, row := range idx {
+		for j := 0; j < c; j++ {
+			res.Set(i, j, m.At(row, j))
+		}
+	}
+	return res
+}
+
+// MatrixRows returns a copy of the rows of m with the given indices.
+func MatrixRows(m cmat.Matrix, idx []int) cmat.Matrix {
+	return cmat.FromFunc(len(idx), m.Cols(), func(i, j int) float64 { return m.At(idx[i], j) })
+}
+
+// A Split contains the indices of the examples in each subset of a dataset.
+type Split struct {
+	Train, Validation, Test []int
+}
+
+// ShuffleSplit randomly assigns n examples to the train, validation and test
+// subsets, where validation and test are the fractions of examples in each.
+// The same seed always produces the same split. It fails if the fractions
+// are negative or add up to more than 1.
+func ShuffleSplit(n int, validation, test float64, seed int64) (Split, error) {
+	if err := checkFractions(validation, test); err != nil {
+		return Split{}, err
+	}
+	perm := rand.New(rand.NewSource(seed)).Perm(n)
+	nVal, nTest := sizes(n, validation, test)
+	return Split{
+		Test:       perm[:nTest],
+		Validation: perm[nTest : nTest+nVal],
+		Train:      perm[nTest+nVal:],
+	}, nil
+}
+
+// StratifiedSplit works like ShuffleSplit, but keeps the proportion of each
+// class in labels the same across the train, validation and test subsets.
+func StratifiedSplit(labels []float64, validation, test float64, seed int64) (Split, error) {
+	if err := checkFractions(validation, test); err != nil {
+		return Split{}, err
+	}
+	rng := rand.New(rand.NewSource(seed))
+
+	var s Split
+	for _, idx := range classes(labels) {
+		rng.Shuffle(len(idx), func(i, j int) { idx[i], idx[j] = idx[j], idx[i] })
+		nVal, nTest := sizes(len(idx), validation, test)
+		s.Test = append(s.Test, idx[:nTest]...)
+		s.Validation = append(s.Validation, idx[nTest:nTest+nVal]...)
+		s.Train = append(s.Train, idx[nTest+nVal:]...)
+	}
+
+	for _, idx := range [][]int{s.Train, s.Validation, s.Test} {
+		rng.Shuffle(len(idx), func(i, j int) { idx[i], idx[j] = idx[j], idx[i] })
+	}
+	return s, nil
+}
+
+// classes groups the indices of labels by their value, sorted by label.
+func classes(labels []float64) [][]int {
+	byLabel := make(map[float64][]int)
+	for i, l := range labels {
+		byLabel[l] = append(byLabel[l], i)
+	}
+	keys := make([]float64, 0, len(byLabel))
+	for l := range byLabel {
+		keys = append(keys, l)
+	}
+	sort.Float64s(keys)
+
+	res := make([][]int, len(keys))
+	for i, l := range keys {
+		res[i] = byLabel[l]
+	}
+	return res
+}
+
+func checkFractions(validation, test float64) error {
+	if !(validation >= 0 && test >= 0 && validation+test <= 1) {
+		return errors.Errorf("invalid split fractions %v and %v", validation, test)
+	}
+	return nil
+}
+
+// sizes returns how many of n examples go to the validation and test subsets.
+func sizes(n int, validation, test float64) (nVal, nTest int) {
+	nVal = int(math.Floor(validation*float64(n) + 0.5))
+	nTest = int(math.Floor(test*float64(n) + 0.5))
+	if nVal+nTest > n {
+		nVal = n - nTest
+	}
+	return nVal, nTest
+}
@@ -0,0 +1,120 @@
+package dataset
+
+import (
+	"bytes"
+	"math"
+	"sort"
+	"testing"
+
+	cmat "github.com/campoy/mat"
+	"gonum.org/v1/gonum/mat"
+
+	"github.com/campoy/goml/util"
+)
+
+func TestShuffleSplit(t *testing.T) {
+	s, err := ShuffleSplit(100, 0.2, 0.1, 42)
+	if err != nil {
+		t.Fatalf("could not split: %v", err)
+	}
+	if len(s.Train) != 70 || len(s.Validation) != 20 || len(s.Test) != 10 {
+		t.Fatalf("expected 70/20/10 split; got %d/%d/%d", len(s.Train), len(s.Validation), len(s.Test))
+	}
+
+	var all []int
+	all = append(append(append(all, s.Train...), s.Validation...), s.Test...)
+	sort.Ints(all)
+	for i, v := range all {
+		if i != v {
+			t.Fatalf("expected every index to appear once; got %v", all)
+		}
+	}
+
+	again, _ := ShuffleSplit(100, 0.2, 0.1, 42)
+	for i := range s.Train {
+		if s.Train[i] != again.Train[i] {
+			t.Fatalf("expected the same seed to produce the same split")
+		}
+	}
+}
+
+func TestStratifiedSplit(t *testing.T) {
+	labels := make([]float64, 100)
+	for i := 80; i < 100; i++ {
+		labels[i] = 1
+	}
+
+	s, err := StratifiedSplit(labels, 0, 0.25, 1)
+	if err != nil {
+		t.Fatalf("could not split: %v", err)
+	}
+	count := func(idx []int) (pos int) {
+		for _, i := range idx {
+			if labels[i] == 1 {
+				pos++
+			}
+		}
+		return pos
+	}
+	if len(s.Test) != 25 || count(s.Test) != 5 {
+		t.Errorf("expected 5 of 25 test examples to be positive; got %d of %d", count(s.Test), len(s.Test))
+	}
+	if len(s.Train) != 75 || count(s.Train) != 15 {
+		t.Errorf("expected 15 of 75 train examples to be positive; got %d of %d", count(s.Train), len(s.Train))
+	}
+}
+
+func TestSplitFractions(t *testing.T) {
+	for _, f := range [][2]float64{{-0.1, 0.2}, {0.6, 0.5}, {math.NaN(), 0}} {
+		if _, err := ShuffleSplit(10, f[0], f[1], 1); err == nil {
+			t.Errorf("expected error for fractions %v", f)
+		}
+		if _, err := StratifiedSplit(make([]float64, 10), f[0], f[1], 1); err == nil {
+			t.Errorf("expected stratified error for fractions %v", f)
+		}
+	}
+}
+
+func TestFromTable(t *testing.T) {
+	tab := &util.Table{
+		Names: []string{"a", "b"},
+		X:     cmat.FromSlice(1, 2, []float64{1, 2}),
+	}
+	d := FromTable(tab)
+	tab.Names[0] = "changed"
+	if d.Names[0] != "a" {
+		t.Errorf("expected names to be copied; got %v", d.Names)
+	}
+	if d.Y != nil || d.Labels() != nil {
+		t.Errorf("expected no targets and no labels; got %v and %v", d.Y, d.Labels())
+	}
+}
+
+func TestSubset(t *testing.T) {
+	d := &Dataset{
+		X: mat.NewDense(3, 2, []float64{1, 2, 3, 4, 5, 6}),
+		Y: mat.NewDense(3, 2, []float64{0, 1, 1, 0, 0, 1}),
+	}
+	s := d.Subset([]int{2, 0})
+	if got := mat.Row(nil, 0, s.X); got[0] != 5 || got[1] != 6 {
+		t.Errorf("expected first row to be [5 6]; got %v", got)
+	}
+	if got := s.Labels(); got[0] != 1 || got[1] != 1 {
+		t.Errorf("expected labels [1 1]; got %v", got)
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	d := &Dataset{
+		X:     mat.NewDense(2, 2, []float64{1, 2, 3, 4}),
+		Y:     mat.NewDense(2, 1, []float64{0, 1}),
+		Names: []string{"a", "b"},
+	}
+	var buf bytes.Buffer
+	if err := d.WriteCSV(&buf, 1); err != nil {
+		t.Fatalf("could not write: %v", err)
+	}
+	if want := "a,b,y\n1.0,2.0,0.0\n3.0,4.0,1.0\n"; buf.String() != want {
+		t.Errorf("expected %q; got %q", want, buf.String())
+	}
+}
@@ -0,0 +1,262 @@
+// Package describe summarizes the columns of a matrix before training,
+// as a text table and as plots written to a util.Sink.
+package describe
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/palette"
+	"gonum.org/v1/plot/plotter"
+
+	"github.com/campoy/goml/util"
+)
+
+// Probs are the probabilities of the quantiles computed for each column.
+var Probs = []float64{0.25, 0.5, 0.75}
+
+// A Column contains the statistics of a single column.
+// Missing values, encoded as NaN, are ignored by all statistics.
+type Column struct {
+	Name      string
+	Count     int // Number of values that are not missing.
+	Missing   int
+	Distinct  int
+	Mean, Std float64
+	Min, Max  float64
+	Quantiles []float64 // Quantiles at each of Probs.
+}
+
+// A Summary contains the statistics of every column of a matrix.
+type Summary struct {
+	Columns []Column
+	// Corr holds the correlation between every pair of columns,
+	// computed over the rows without missing values. It is NaN for
+	// columns that are constant over those rows, since their correlation
+	// is undefined, and such values are shown as NA.
+	Corr *mat.SymDense
+
+	values [][]float64 // Sorted values of each column, without missing ones.
+}
+
+// Matrix computes the summary of the columns of m, with the given names.
+// Columns are named by their index when names is nil.
+func Matrix(m util.Matrix, names []string) (*Summary, error) {
+	r, c := m.Dims()
+	if names != nil && len(names) != c {
+		return nil, errors.Errorf("got %d names for %d columns", len(names), c)
+	}
+	s := &Summary{Columns: make([]Column, c), values: make([][]float64, c)}
+
+	var complete []float64
+	completeRows := 0
+	for i := 0; i < r; i++ {
+		ok := true
+		for j := 0; j < c; j++ {
+			if v := m.At(i, j); math.IsNaN(v) {
+				ok = false
+			} else {
+				s.values[j] = append(s.values[j], v)
+			}
+		}
+		if ok {
+			for j := 0; j < c; j++ {
+				complete = append(complete, m.At(i, j))
+			}
+			completeRows++
+		}
+	}
+
+	for j := range s.Columns {
+		col := &s.Columns[j]
+		col.Name = fmt.Sprint(j)
+		if names != nil {
+			col.Name = names[j]
+		}
+		vs := s.values[j]
+		sort.Float64s(vs)
+		col.Count = len(vs)
+		col.Missing = r - len(vs)
+		col.Quantiles = make([]float64, len(Probs))
+		if len(vs) == 0 {
+			col.Mean, col.Std, col.Min, col.Max = math.NaN(), math.NaN(), math.NaN(), math.NaN()
+			for k := range col.Quantiles {
+				col.Quantiles[k] = math.NaN()
+			}
+			continue
+		}
+
+		col.Mean, col.Std = stat.MeanStdDev(vs, nil)
+		col.Min, col.Max = vs[0], vs[len(vs)-1]
+		for k, p := range Probs {
+			col.Quantiles[k] = stat.Quantile(p, stat.Empirical, vs, nil)
+		}
+		col.Distinct = 1
+		for k := 1; k < len(vs); k++ {
+			if vs[k] != vs[k-1] {
+				col.Distinct++
+			}
+		}
+	}
+
+	if completeRows > 1 {
+		s.Corr = mat.NewSymDense(c, nil)
+		data := mat.NewDense(completeRows, c, complete)
+		stat.CorrelationMatrix(s.Corr, data, nil)
+		for j := 0; j < c; j++ {
+			if col := mat.Col(nil, j, data); floats.Min(col) == floats.Max(col) {
+				for k := 0; k < c; k++ {
+					s.Corr.SetSym(j, k, math.NaN())
+				}
+			}
+		}
+	}
+	return s, nil
+}
+
+// String returns the summary as a text table, followed by the correlation matrix.
+func (s *Summary) String() string {
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 4, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprint(w, "\tcount\tmissing\tdistinct\tmean\tstd\tmin\t")
+	for _, p := range Probs {
+		fmt.Fprintf(w, "%g%%\t", 100*p)
+	}
+	fmt.Fprintln(w, "max\t")
+	for _, c := range s.Columns {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%.4g\t%.4g\t%.4g\t", c.Name, c.Count, c.Missing, c.Distinct, c.Mean, c.Std, c.Min)
+		for _, q := range c.Quantiles {
+			fmt.Fprintf(w, "%.4g\t", q)
+		}
+		fmt.Fprintf(w, "%.4g\t\n", c.Max)
+	}
+
+	if s.Corr != nil {
+		fmt.Fprintln(w, "\t")
+		fmt.Fprint(w, "correlation\t")
+		for _, c := range s.Columns {
+			fmt.Fprintf(w, "%s\t", c.Name)
+		}
+		fmt.Fprintln(w)
+		for i, c := range s.Columns {
+			fmt.Fprintf(w, "%s\t", c.Name)
+			for j := range s.Columns {
+				if v := s.Corr.At(i, j); math.IsNaN(v) {
+					fmt.Fprint(w, "NA\t")
+				} else {
+					fmt.Fprintf(w, "%.3f\t", v)
+				}
+			}
+			fmt.Fprintln(w)
+		}
+	}
+	w.Flush()
+	return buf.String()
+}
+
+// Histogram returns a plot with the histogram of the values of column j.
+func (s *Summary) Histogram(j, bins int) (*plot.Plot, error) {
+	if len(s.values[j]) == 0 {
+		return nil, errors.Errorf("column %s has no values", s.Columns[j].Name)
+	}
+	p, err := plot.New()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create plot")
+	}
+	h, err := plotter.NewHist(plotter.Values(s.values[j]), bins)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create histogram")
+	}
+	p.Add(h)
+	p.Title.Text = s.Columns[j].Name
+	p.Y.Label.Text = "count"
+	return p, nil
+}
+
+// Heatmap returns a plot of the correlation matrix. Undefined correlations
+// are drawn with the color of 0 and labeled NA.
+func (s *Summary) Heatmap() (*plot.Plot, error) {
+	if s.Corr == nil {
+		return nil, errors.New("not enough complete rows to compute correlations")
+	}
+	p, err := plot.New()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create plot")
+	}
+	h := plotter.NewHeatMap(corrGrid{s.Corr}, palette.Heat(32, 1))
+	h.Min, h.Max = -1, 1
+	p.Add(h)
+
+	var na plotter.XYLabels
+	n := s.Corr.SymmetricDim()
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			if math.IsNaN(s.Corr.At(i, j)) {
+				na.XYs = append(na.XYs, plotter.XY{X: float64(j), Y: float64(i)})
+				na.Labels = append(na.Labels, "NA")
+			}
+		}
+	}
+	if len(na.XYs) > 0 {
+		l, err := plotter.NewLabels(na)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not create labels")
+		}
+		p.Add(l)
+	}
+
+	var ticks []plot.Tick
+	for i, c := range s.Columns {
+		ticks = append(ticks, plot.Tick{Value: float64(i), Label: c.Name})
+	}
+	p.X.Tick.Marker = plot.ConstantTicks(ticks)
+	p.Y.Tick.Marker = plot.ConstantTicks(ticks)
+	p.Title.Text = "Correlation"
+	return p, nil
+}
+
+// corrGrid can be used as a plotter.GridXYZ for the correlation matrix.
+type corrGrid struct{ m *mat.SymDense }
+
+func (g corrGrid) Dims() (int, int) { n := g.m.SymmetricDim(); return n, n }
+func (g corrGrid) X(c int) float64  { return float64(c) }
+func (g corrGrid) Y(r int) float64  { return float64(r) }
+func (g corrGrid) Z(c, r int) float64 {
+	if v := g.m.At(r, c); !math.IsNaN(v) {
+		return v
+	}
+	return 0
+}
+
+// WritePlots writes a histogram with the given number of bins for every
+// column with values, followed by the correlation heatmap, to the sink.
+func (s *Summary) WritePlots(sink util.Sink, bins int) error {
+	for j := range s.Columns {
+		if len(s.values[j]) == 0 {
+			continue
+		}
+		p, err := s.Histogram(j, bins)
+		if err != nil {
+			return err
+		}
+		if err := sink.WritePlot(p, 400, 300); err != nil {
+			return err
+		}
+	}
+	if s.Corr == nil {
+		return nil
+	}
+	p, err := s.Heatmap()
+	if err != nil {
+		return err
+	}
+	return sink.WritePlot(p, 400, 400)
+}
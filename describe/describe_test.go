@@ -0,0 +1,64 @@
+package describe
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestMatrix(t *testing.T) {
+	nan := math.NaN()
+	m := mat.NewDense(5, 3, []float64{
+		1, 2, 5,
+		2, 4, 5,
+		3, 6, nan,
+		4, 8, 5,
+		nan, 10, 5,
+	})
+	s, err := Matrix(m, []string{"a", "b", "c"})
+	if err != nil {
+		t.Fatalf("could not describe: %v", err)
+	}
+
+	a := s.Columns[0]
+	if a.Count != 4 || a.Missing != 1 || a.Distinct != 4 {
+		t.Errorf("expected a to have 4 values, 1 missing and 4 distinct; got %d, %d and %d",
+			a.Count, a.Missing, a.Distinct)
+	}
+	if a.Mean != 2.5 || a.Min != 1 || a.Max != 4 || a.Quantiles[1] != 2 {
+		t.Errorf("unexpected statistics for a: %+v", a)
+	}
+	if c := s.Columns[2]; c.Distinct != 1 || c.Std != 0 {
+		t.Errorf("expected c to be constant; got %+v", c)
+	}
+	if got := s.Corr.At(0, 1); math.Abs(got-1) > 1e-12 {
+		t.Errorf("expected a and b to be perfectly correlated; got %v", got)
+	}
+
+	if got := s.Corr.At(0, 2); !math.IsNaN(got) {
+		t.Errorf("expected correlation with constant c to be NaN; got %v", got)
+	}
+
+	out := s.String()
+	for _, want := range []string{"count", "missing", "50%", "correlation"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected summary to contain %q; got\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "NaN") {
+		t.Errorf("expected no NaN in summary; got\n%s", out)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if last := strings.Fields(lines[len(lines)-1]); strings.Join(last, " ") != "c NA NA NA" {
+		t.Errorf("expected correlations of c to be NA; got %v", last)
+	}
+
+	if _, err := s.Heatmap(); err != nil {
+		t.Errorf("could not plot heatmap: %v", err)
+	}
+	if _, err := Matrix(m, []string{"a", "b"}); err == nil {
+		t.Errorf("expected error for missing names")
+	}
+}
@@ -0,0 +1,117 @@
+// Package features transforms feature matrices before training, so linear
+// models can learn relations that are not linear in the original features.
+package features
+
+import (
+	"fmt"
+	"strings"
+
+	"gonum.org/v1/gonum/mat"
+
+	"github.com/campoy/goml/util"
+)
+
+// A Polynomial expands a matrix of features into all the products of up to
+// Degree of them, such as x1, x2, x1^2, x1*x2 and x2^2 for two features and
+// degree 2. With a degree of 6 and two features this is the mapFeature
+// function used to fit the non-linear boundary of logreg/ex2data2.txt.
+//
+// Terms are sorted by degree, and then by the features they contain, so the
+// first columns of the expansion hold the original features.
+type Polynomial struct {
+	Degree int
+	// InteractionOnly skips the terms where a feature appears more than once,
+	// such as x1^2, keeping only the products of distinct features.
+	InteractionOnly bool
+	// NoBias skips the column of ones that is otherwise the first one.
+	NoBias bool
+}
+
+// Terms returns the terms of the expansion of n features, where each term is
+// a list of the indices of the features multiplied in it, in increasing order.
+// The bias term, when included, is the empty list.
+func (p Polynomial) Terms(n int) [][]int {
+	var terms [][]int
+	if !p.NoBias {
+		terms = append(terms, []int{})
+	}
+	var add func(term []int, from, degree int)
+	add = func(term []int, from, degree int) {
+		if len(term) == degree {
+			terms = append(terms, append([]int(nil), term...))
+			return
+		}
+		for j := from; j < n; j++ {
+			next := j
+			if p.InteractionOnly {
+				next = j + 1
+			}
+			add(append(term, j), next, degree)
+		}
+	}
+	for d := 1; d <= p.Degree; d++ {
+		add(nil, 0, d)
+	}
+	return terms
+}
+
+// Width returns the number of columns of the expansion of n features.
+func (p Polynomial) Width(n int) int { return len(p.Terms(n)) }
+
+// Names returns the names of the columns of the expansion, such as "x1^2*x2",
+// given the names of the n original features. Features are named x1, x2, and
+// so on when names is nil, and the bias column is named "1".
+func (p Polynomial) Names(n int, names []string) []string {
+	if names == nil {
+		names = make([]string, n)
+		for j := range names {
+			names[j] = fmt.Sprintf("x%d", j+1)
+		}
+	}
+	terms := p.Terms(n)
+	out := make([]string, len(terms))
+	for t, term := range terms {
+		if len(term) == 0 {
+			out[t] = "1"
+			continue
+		}
+		var parts []string
+		for k := 0; k < len(term); {
+			e := 1
+			for k+e < len(term) && term[k+e] == term[k] {
+				e++
+			}
+			if e == 1 {
+				parts = append(parts, names[term[k]])
+			} else {
+				parts = append(parts, fmt.Sprintf("%s^%d", names[term[k]], e))
+			}
+			k += e
+		}
+		out[t] = strings.Join(parts, "*")
+	}
+	return out
+}
+
+// Transform returns the expansion of the features in X, with a row per row
+// of X and a column per term, as returned by Terms.
+func (p Polynomial) Transform(X util.Matrix) *mat.Dense {
+	r, c := X.Dims()
+	terms := p.Terms(c)
+	out := mat.NewDense(r, len(terms), nil)
+	x := make([]float64, c)
+	for i := 0; i < r; i++ {
+		for j := range x {
+			x[j] = X.At(i, j)
+		}
+		row := out.RawRowView(i)
+		for t, term := range terms {
+			v := 1.0
+			for _, j := range term {
+				v *= x[j]
+			}
+			row[t] = v
+		}
+	}
+	return out
+}
@@ -0,0 +1,68 @@
+package features
+
+import (
+	"strings"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestPolynomial(t *testing.T) {
+	X := mat.NewDense(2, 2, []float64{2, 3, -1, 0.5})
+
+	tc := []struct {
+		name  string
+		poly  Polynomial
+		names []string
+		want  [][]float64
+	}{
+		{
+			name:  "degree 2",
+			poly:  Polynomial{Degree: 2},
+			names: []string{"1", "x1", "x2", "x1^2", "x1*x2", "x2^2"},
+			want:  [][]float64{{1, 2, 3, 4, 6, 9}, {1, -1, 0.5, 1, -0.5, 0.25}},
+		},
+		{
+			name:  "degree 3 without bias",
+			poly:  Polynomial{Degree: 3, NoBias: true},
+			names: []string{"x1", "x2", "x1^2", "x1*x2", "x2^2", "x1^3", "x1^2*x2", "x1*x2^2", "x2^3"},
+			want: [][]float64{
+				{2, 3, 4, 6, 9, 8, 12, 18, 27},
+				{-1, 0.5, 1, -0.5, 0.25, -1, 0.5, -0.25, 0.125},
+			},
+		},
+		{
+			name:  "interaction only",
+			poly:  Polynomial{Degree: 3, InteractionOnly: true},
+			names: []string{"1", "x1", "x2", "x1*x2"},
+			want:  [][]float64{{1, 2, 3, 6}, {1, -1, 0.5, -0.5}},
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, want := strings.Join(tt.poly.Names(2, nil), " "), strings.Join(tt.names, " "); got != want {
+				t.Errorf("expected names %q; got %q", want, got)
+			}
+			got := tt.poly.Transform(X)
+			if r, c := got.Dims(); r != 2 || c != tt.poly.Width(2) {
+				t.Fatalf("expected 2x%d matrix; got %dx%d", tt.poly.Width(2), r, c)
+			}
+			if want := mat.NewDense(2, len(tt.names), append(tt.want[0], tt.want[1]...)); !mat.Equal(got, want) {
+				t.Errorf("expected %v; got %v", mat.Formatted(want), mat.Formatted(got))
+			}
+		})
+	}
+}
+
+func TestPolynomialMapFeature(t *testing.T) {
+	// mapFeature expands two features into the 28 terms up to degree 6.
+	p := Polynomial{Degree: 6}
+	if got := p.Width(2); got != 28 {
+		t.Errorf("expected 28 terms; got %d", got)
+	}
+	names := p.Names(2, []string{"u", "v"})
+	if got, want := names[len(names)-2], "u*v^5"; got != want {
+		t.Errorf("expected name %q; got %q", want, got)
+	}
+}
@@ -1,7 +1,7 @@
 package iplot
 
 import (
-	"github.com/gonum/plot/plotter"
+	"gonum.org/v1/plot/plotter"
 )
 
 // A Range represents a range of values with steps.
@@ -42,3 +42,12 @@ func TestRange(t *testing.T) {
 		})
 	}
 }
+
+func TestPercentile(t *testing.T) {
+	tc := map[float64]string{0.1: "p10", 0.07: "p7", 0.29: "p29", 0.5: "p50", 0.125: "p12.5", 0.975: "p97.5"}
+	for tau, want := range tc {
+		if got := percentile(tau); got != want {
+			t.Errorf("expected percentile of %v to be %s; got %s", tau, want, got)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package iplot
+
+import (
+	"math"
+
+	"github.com/pkg/errors"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+)
+
+// LearningRates returns a plot of the loss obtained with each learning rate,
+// such as the ones returned by schedule.RangeTest. Learning rates are shown in
+// logarithmic scale, and losses that are not finite are skipped.
+func LearningRates(alphas, losses []float64) (*plot.Plot, error) {
+	if len(alphas) != len(losses) {
+		return nil, errors.Errorf("got %d losses for %d learning rates", len(losses), len(alphas))
+	}
+	var xys plotter.XYs
+	for i, a := range alphas {
+		if a <= 0 {
+			return nil, errors.Errorf("learning rates must be positive, got %v", a)
+		}
+		if math.IsNaN(losses[i]) || math.IsInf(losses[i], 0) {
+			continue
+		}
+		xys = append(xys, plotter.XY{X: math.Log10(a), Y: losses[i]})
+	}
+
+	p, err := plot.New()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create plot")
+	}
+	l, err := plotter.NewLine(xys)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create line")
+	}
+	p.Add(l)
+	p.Title.Text = "Learning rate range test"
+	p.X.Label.Text = "log10(learning rate)"
+	p.Y.Label.Text = "loss"
+	return p, nil
+}
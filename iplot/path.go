@@ -0,0 +1,52 @@
+package iplot
+
+import (
+	"math"
+
+	"github.com/pkg/errors"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/plotutil"
+)
+
+// RegularizationPath returns a plot with a line per column of coefs, showing
+// how each coefficient changes with the regularization parameter. The ith row
+// of coefs holds the coefficients fitted with lambdas[i], and lambdas are
+// shown in logarithmic scale, so they must be positive. Lines are named after
+// the given names, if any.
+func RegularizationPath(lambdas []float64, coefs mat.Matrix, names []string) (*plot.Plot, error) {
+	r, c := coefs.Dims()
+	if r != len(lambdas) {
+		return nil, errors.Errorf("got %d rows of coefficients for %d lambdas", r, len(lambdas))
+	}
+	for _, l := range lambdas {
+		if l <= 0 {
+			return nil, errors.Errorf("lambdas must be positive, got %v", l)
+		}
+	}
+
+	p, err := plot.New()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create plot")
+	}
+	for j := 0; j < c; j++ {
+		xys := make(plotter.XYs, r)
+		for i, l := range lambdas {
+			xys[i].X, xys[i].Y = math.Log10(l), coefs.At(i, j)
+		}
+		l, err := plotter.NewLine(xys)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not create line")
+		}
+		l.Color = plotutil.Color(j)
+		p.Add(l)
+		if j < len(names) {
+			p.Legend.Add(names[j], l)
+		}
+	}
+	p.Title.Text = "Regularization path"
+	p.X.Label.Text = "log10(lambda)"
+	p.Y.Label.Text = "coefficient"
+	return p, nil
+}
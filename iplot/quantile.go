@@ -0,0 +1,62 @@
+package iplot
+
+import (
+	"strconv"
+
+	"github.com/pkg/errors"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/plotutil"
+)
+
+// QuantileBands returns a scatter plot of the second column of X against y,
+// with the first column being the intercept as returned by
+// linreg.InitParameters, and a line per fitted quantile on top of it. The kth
+// column of thetas holds the intercept and slope fitted for taus[k], as
+// returned by linreg.QuantileRegression. Lines are named after the percentile
+// they fit, such as p10 for 0.1.
+func QuantileBands(X, y mat.Matrix, taus []float64, thetas mat.Matrix) (*plot.Plot, error) {
+	m, n := X.Dims()
+	if n != 2 {
+		return nil, errors.Errorf("expected an intercept and a feature, got %d columns", n)
+	}
+	if r, _ := y.Dims(); r != m {
+		return nil, errors.Errorf("got %d targets for %d examples", r, m)
+	}
+	if r, c := thetas.Dims(); r != 2 || c != len(taus) {
+		return nil, errors.Errorf("expected 2x%d coefficients, got %dx%d", len(taus), r, c)
+	}
+
+	p, err := plot.New()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create plot")
+	}
+	xys := make(plotter.XYs, m)
+	for i := range xys {
+		xys[i].X, xys[i].Y = X.At(i, 1), y.At(i, 0)
+	}
+	s, err := plotter.NewScatter(xys)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create scatter")
+	}
+	s.Radius = 1
+	p.Add(s)
+
+	for k, tau := range taus {
+		a, b := thetas.At(0, k), thetas.At(1, k)
+		f := plotter.NewFunction(func(x float64) float64 { return a + b*x })
+		f.Color = plotutil.Color(k)
+		p.Add(f)
+		p.Legend.Add(percentile(tau), f)
+	}
+	p.Title.Text = "Quantile regression"
+	return p, nil
+}
+
+// percentile returns the name of the percentile of the quantile tau, such as
+// p10 for 0.1. It is rounded to four significant digits, since multiplying by
+// 100 gives values such as 7.000000000000001 for 0.07.
+func percentile(tau float64) string {
+	return "p" + strconv.FormatFloat(100*tau, 'g', 4, 64)
+}
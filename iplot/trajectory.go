@@ -0,0 +1,83 @@
+package iplot
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/palette"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/plotutil"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// Trajectories returns a plot with a line per parameter, showing its value in
+// each of the given thetas against the step it was recorded at, as kept by
+// linreg.History. Lines are named after the given names, or theta0, theta1,
+// and so on when names is nil.
+func Trajectories(steps []int, thetas [][]float64, names []string) (*plot.Plot, error) {
+	if len(steps) != len(thetas) {
+		return nil, errors.Errorf("got %d thetas for %d steps", len(thetas), len(steps))
+	}
+	if len(thetas) == 0 {
+		return nil, errors.New("no thetas to plot")
+	}
+
+	p, err := plot.New()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create plot")
+	}
+	for j := range thetas[0] {
+		xys := make(plotter.XYs, len(thetas))
+		for i, theta := range thetas {
+			xys[i].X, xys[i].Y = float64(steps[i]), theta[j]
+		}
+		l, err := plotter.NewLine(xys)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not create line")
+		}
+		l.Color = plotutil.Color(j)
+		p.Add(l)
+		name := fmt.Sprintf("theta%d", j)
+		if j < len(names) {
+			name = names[j]
+		}
+		p.Legend.Add(name, l)
+	}
+	p.Title.Text = "Parameters over time"
+	p.X.Label.Text = "number of iterations"
+	return p, nil
+}
+
+// ContourPath returns a contour plot of the cost function f over the given
+// ranges of the ith and jth parameters, with the path followed by those two
+// parameters in the given thetas on top of it.
+//
+// When theta has more than two parameters, f should compute the cost with the
+// rest of them fixed, for instance to their final values.
+func ContourPath(x, y Range, f func(a, b float64) float64, thetas [][]float64, i, j int) (*plot.Plot, error) {
+	p, err := plot.New()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create plot")
+	}
+	p.Add(plotter.NewContour(GridXYZ(x, y, f), nil, palette.Heat(16, 1)))
+
+	xys := make(plotter.XYs, len(thetas))
+	for k, theta := range thetas {
+		if i >= len(theta) || j >= len(theta) {
+			return nil, errors.Errorf("parameters %d and %d out of range for %d parameters", i, j, len(theta))
+		}
+		xys[k].X, xys[k].Y = theta[i], theta[j]
+	}
+	s, err := plotter.NewScatter(xys)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create scatter")
+	}
+	s.Radius = 1
+	s.Shape = draw.CrossGlyph{}
+	p.Add(s)
+	p.Title.Text = "Optimization path"
+	p.X.Label.Text = fmt.Sprintf("theta%d", i)
+	p.Y.Label.Text = fmt.Sprintf("theta%d", j)
+	return p, nil
+}
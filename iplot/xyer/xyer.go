@@ -3,27 +3,30 @@ package xyer
 import (
 	"fmt"
 
-	"github.com/campoy/mat"
 	"gonum.org/v1/plot/plotter"
+
+	"github.com/campoy/goml/util"
 )
 
-// // FromMatrices returns an XYer from two matrices.
-// func FromMatrices(x, y mat.Matrix) plotter.XYer { return matrices{x, y} }
+// FromMatrices returns an XYer from the first column of two matrices,
+// which can be campoy or gonum matrices.
+func FromMatrices(x, y util.Matrix) plotter.XYer { return matrices{x, y} }
 
-// type matrices struct{ x, y mat.Matrix }
+type matrices struct{ x, y util.Matrix }
 
-// func (m matrices) XY(i int) (x, y float64) { return m.x.At(i, 0), m.y.At(i, 0) }
-// func (m matrices) Len() int                { s, _ := m.x.Dims(); return s }
+func (m matrices) XY(i int) (x, y float64) { return m.x.At(i, 0), m.y.At(i, 0) }
+func (m matrices) Len() int                { s, _ := m.x.Dims(); return s }
 
-func FromMatrixCols(m mat.Matrix, x, y int) plotter.XYer { return matrixCols{m, x, y} }
+// FromMatrixCols returns an XYer from two columns of a campoy or gonum matrix.
+func FromMatrixCols(m util.Matrix, x, y int) plotter.XYer { return matrixCols{m, x, y} }
 
 type matrixCols struct {
-	m    mat.Matrix
+	m    util.Matrix
 	x, y int
 }
 
 func (m matrixCols) XY(i int) (x, y float64) { return m.m.At(i, m.x), m.m.At(i, m.y) }
-func (m matrixCols) Len() int                { return m.m.Rows() }
+func (m matrixCols) Len() int                { r, _ := m.m.Dims(); return r }
 
 func FromSlice(x []float64) plotter.XYer { return slice(x) }
 
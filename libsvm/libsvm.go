@@ -0,0 +1,282 @@
+// Package libsvm reads and writes datasets in the sparse text format used by
+// LIBSVM and SVMlight, where each line contains an example such as
+//
+//	<label>[,<label>...] [qid:<id>] <index>:<value> ... [# comment]
+package libsvm
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gonum.org/v1/gonum/mat"
+)
+
+// A Feature is a non-zero value in a sparse row.
+// Its index is the zero-based column it belongs to.
+type Feature struct {
+	Index int
+	Value float64
+}
+
+// An Example is a single line of a LIBSVM file.
+type Example struct {
+	Labels   []float64
+	QID      int       // Query id, only meaningful if Data.HasQID is set.
+	Features []Feature // Sorted by index.
+}
+
+// Data contains the examples in a LIBSVM file.
+type Data struct {
+	Examples    []Example
+	NumFeatures int  // Number of columns, one more than the largest index.
+	HasQID      bool // Whether the examples have query ids.
+}
+
+// ReadFile reads the LIBSVM file at the given path, see Read.
+func ReadFile(path string, numFeatures int) (*Data, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not read %s", path)
+	}
+	defer f.Close()
+
+	d, err := Read(f, numFeatures)
+	return d, errors.Wrapf(err, "could not parse %s", path)
+}
+
+// Read reads LIBSVM formatted examples from r. Indices in the file are
+// one-based, unless an index 0 appears in which case they are zero-based.
+//
+// The format only stores non-zero values, so the number of features cannot
+// be known when the last columns are all zeros. When numFeatures is positive
+// the data has that many features, and larger indices are an error. When it
+// is 0 the number of features is one more than the largest index.
+func Read(r io.Reader, numFeatures int) (*Data, error) {
+	if numFeatures < 0 {
+		return nil, errors.Errorf("number of features must not be negative, got %d", numFeatures)
+	}
+	d := &Data{NumFeatures: numFeatures}
+	zeroBased := false
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, 1<<26)
+	for line := 1; s.Scan(); line++ {
+		text := s.Text()
+		if i := strings.IndexByte(text, '#'); i >= 0 {
+			text = text[:i]
+		}
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			continue
+		}
+
+		ex, qid, err := parseExample(fields)
+		if err != nil {
+			return nil, errors.Wrapf(err, "line %d", line)
+		}
+		if qid {
+			d.HasQID = true
+		}
+		for _, f := range ex.Features {
+			if f.Index == 0 {
+				zeroBased = true
+			}
+		}
+		d.Examples = append(d.Examples, ex)
+	}
+	if err := s.Err(); err != nil {
+		return nil, errors.Wrap(err, "could not read examples")
+	}
+
+	for _, ex := range d.Examples {
+		for i := range ex.Features {
+			if !zeroBased {
+				ex.Features[i].Index--
+			}
+			n := ex.Features[i].Index + 1
+			if numFeatures > 0 && n > numFeatures {
+				return nil, errors.Errorf("feature index %d out of range for %d features", ex.Features[i].Index, numFeatures)
+			}
+			if n > d.NumFeatures {
+				d.NumFeatures = n
+			}
+		}
+	}
+	return d, nil
+}
+
+// parseExample parses the fields of a line, keeping indices as in the file.
+// It also reports whether the example had a query id.
+func parseExample(fields []string) (ex Example, qid bool, err error) {
+	if !strings.Contains(fields[0], ":") {
+		for _, l := range strings.Split(fields[0], ",") {
+			if l == "" {
+				continue
+			}
+			v, err := strconv.ParseFloat(l, 64)
+			if err != nil {
+				return ex, false, errors.Wrapf(err, "could not parse label %q", l)
+			}
+			ex.Labels = append(ex.Labels, v)
+		}
+		fields = fields[1:]
+	}
+
+	for _, f := range fields {
+		p := strings.IndexByte(f, ':')
+		if p < 0 {
+			return ex, false, errors.Errorf("bad feature %q", f)
+		}
+		key, val := f[:p], f[p+1:]
+		if key == "qid" {
+			if ex.QID, err = strconv.Atoi(val); err != nil {
+				return ex, false, errors.Wrapf(err, "could not parse qid %q", val)
+			}
+			qid = true
+			continue
+		}
+
+		idx, err := strconv.Atoi(key)
+		if err != nil || idx < 0 {
+			return ex, false, errors.Errorf("bad feature index %q", key)
+		}
+		v, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return ex, false, errors.Wrapf(err, "could not parse value of feature %d", idx)
+		}
+		ex.Features = append(ex.Features, Feature{idx, v})
+	}
+
+	sort.Slice(ex.Features, func(i, j int) bool { return ex.Features[i].Index < ex.Features[j].Index })
+	for i := 1; i < len(ex.Features); i++ {
+		if ex.Features[i].Index == ex.Features[i-1].Index {
+			return ex, false, errors.Errorf("duplicated feature index %d", ex.Features[i].Index)
+		}
+	}
+	return ex, qid, nil
+}
+
+// X returns the features of the examples as a dense matrix.
+func (d *Data) X() *mat.Dense {
+	X := mat.NewDense(len(d.Examples), d.NumFeatures, nil)
+	for i, ex := range d.Examples {
+		for _, f := range ex.Features {
+			X.Set(i, f.Index, f.Value)
+		}
+	}
+	return X
+}
+
+// Y returns a column vector with the label of each example.
+// It fails if any example does not have exactly one label.
+func (d *Data) Y() (*mat.Dense, error) {
+	y := mat.NewDense(len(d.Examples), 1, nil)
+	for i, ex := range d.Examples {
+		if len(ex.Labels) != 1 {
+			return nil, errors.Errorf("example %d has %d labels", i, len(ex.Labels))
+		}
+		y.Set(i, 0, ex.Labels[0])
+	}
+	return y, nil
+}
+
+// MultiLabel returns a matrix with a column per distinct label, in the
+// returned order, set to 1 on the examples that have that label.
+func (d *Data) MultiLabel() (*mat.Dense, []float64) {
+	pos := make(map[float64]int)
+	var labels []float64
+	for _, ex := range d.Examples {
+		for _, l := range ex.Labels {
+			if _, ok := pos[l]; !ok {
+				pos[l] = len(labels)
+				labels = append(labels, l)
+			}
+		}
+	}
+	if len(labels) == 0 {
+		return nil, nil
+	}
+	sort.Float64s(labels)
+	for i, l := range labels {
+		pos[l] = i
+	}
+
+	y := mat.NewDense(len(d.Examples), len(labels), nil)
+	for i, ex := range d.Examples {
+		for _, l := range ex.Labels {
+			y.Set(i, pos[l], 1)
+		}
+	}
+	return y, labels
+}
+
+// FromDense returns the examples with the features in the rows of X and the
+// labels in the rows of y, keeping only the non-zero features. Each column of
+// y is taken as a label, so hot encoded matrices should be decoded first.
+// The examples have no labels when y is nil.
+func FromDense(X mat.Matrix, y *mat.Dense) *Data {
+	m, n := X.Dims()
+	k := 0
+	if y != nil {
+		_, k = y.Dims()
+	}
+	d := &Data{Examples: make([]Example, m), NumFeatures: n}
+	for i := range d.Examples {
+		ex := &d.Examples[i]
+		for j := 0; j < k; j++ {
+			ex.Labels = append(ex.Labels, y.At(i, j))
+		}
+		for j := 0; j < n; j++ {
+			if v := X.At(i, j); v != 0 {
+				ex.Features = append(ex.Features, Feature{j, v})
+			}
+		}
+	}
+	return d
+}
+
+// WriteFile writes the examples to the file at the given path.
+func WriteFile(path string, d *Data) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return errors.Wrapf(err, "could not create %s", path)
+	}
+	if err := Write(f, d); err != nil {
+		f.Close()
+		return err
+	}
+	return errors.Wrapf(f.Close(), "could not close %s", path)
+}
+
+// Write writes the examples to w with one-based indices. The number of
+// features is not stored, so it must be given to Read when the last
+// features can be all zeros.
+func Write(w io.Writer, d *Data) error {
+	bw := bufio.NewWriter(w)
+	for _, ex := range d.Examples {
+		for i, l := range ex.Labels {
+			if i > 0 {
+				bw.WriteByte(',')
+			}
+			bw.WriteString(formatFloat(l))
+		}
+		if d.HasQID {
+			bw.WriteString(" qid:")
+			bw.WriteString(strconv.Itoa(ex.QID))
+		}
+		for _, f := range ex.Features {
+			bw.WriteByte(' ')
+			bw.WriteString(strconv.Itoa(f.Index + 1))
+			bw.WriteByte(':')
+			bw.WriteString(formatFloat(f.Value))
+		}
+		bw.WriteByte('\n')
+	}
+	return errors.Wrap(bw.Flush(), "could not write examples")
+}
+
+func formatFloat(v float64) string { return strconv.FormatFloat(v, 'g', -1, 64) }
@@ -0,0 +1,113 @@
+package libsvm
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestRead(t *testing.T) {
+	const in = `# a comment
+1 qid:3 1:0.5 3:-2
+0 qid:3 2:1.5 # trailing comment
+
+1,2 qid:4 3:1
+`
+	d, err := Read(strings.NewReader(in), 0)
+	if err != nil {
+		t.Fatalf("could not read: %v", err)
+	}
+	if len(d.Examples) != 3 || d.NumFeatures != 3 || !d.HasQID {
+		t.Fatalf("expected 3 examples with 3 features and qids; got %d, %d and %v",
+			len(d.Examples), d.NumFeatures, d.HasQID)
+	}
+	if d.Examples[2].QID != 4 {
+		t.Errorf("expected qid 4; got %d", d.Examples[2].QID)
+	}
+
+	want := mat.NewDense(3, 3, []float64{0.5, 0, -2, 0, 1.5, 0, 0, 0, 1})
+	if X := d.X(); !mat.Equal(X, want) {
+		t.Errorf("expected X\n%v\ngot\n%v", mat.Formatted(want), mat.Formatted(X))
+	}
+
+	if _, err := d.Y(); err == nil {
+		t.Errorf("expected error getting single labels from multi-label data")
+	}
+	y, labels := d.MultiLabel()
+	wantY := mat.NewDense(3, 3, []float64{0, 1, 0, 1, 0, 0, 0, 1, 1})
+	if len(labels) != 3 || !mat.Equal(y, wantY) {
+		t.Errorf("expected labels [0 1 2] and y\n%v\ngot %v and\n%v", mat.Formatted(wantY), labels, mat.Formatted(y))
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	tc := []struct{ name, in, msg string }{
+		{"bad label", "a 1:2\n", "line 1: could not parse label"},
+		{"bad index", "1 1:2\n1 x:2\n", `line 2: bad feature index "x"`},
+		{"bad value", "1 1:x\n", "could not parse value of feature 1"},
+		{"duplicated index", "1 1:2 1:3\n", "duplicated feature index 1"},
+	}
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Read(strings.NewReader(tt.in), 0)
+			if err == nil || !strings.Contains(err.Error(), tt.msg) {
+				t.Errorf("expected error containing %q; got %v", tt.msg, err)
+			}
+		})
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	X := mat.NewDense(2, 3, []float64{1, 0, 2.5, 0, 0, -1})
+	y := mat.NewDense(2, 1, []float64{1, -1})
+
+	var buf bytes.Buffer
+	if err := Write(&buf, FromDense(X, y)); err != nil {
+		t.Fatalf("could not write: %v", err)
+	}
+	if want := "1 1:1 3:2.5\n-1 3:-1\n"; buf.String() != want {
+		t.Errorf("expected %q; got %q", want, buf.String())
+	}
+
+	d, err := Read(&buf, 0)
+	if err != nil {
+		t.Fatalf("could not read: %v", err)
+	}
+	gotY, err := d.Y()
+	if err != nil {
+		t.Fatalf("could not get labels: %v", err)
+	}
+	if !mat.Equal(d.X(), X) || !mat.Equal(gotY, y) {
+		t.Errorf("round trip changed the data: %v %v", mat.Formatted(d.X()), mat.Formatted(gotY))
+	}
+}
+
+func TestRoundTripTrailingZeros(t *testing.T) {
+	X := mat.NewDense(2, 4, []float64{1, 0, 2, 0, 0, 3, 0, 0})
+
+	var buf bytes.Buffer
+	if err := Write(&buf, FromDense(X, nil)); err != nil {
+		t.Fatalf("could not write: %v", err)
+	}
+	out := buf.String()
+
+	d, err := Read(strings.NewReader(out), 4)
+	if err != nil {
+		t.Fatalf("could not read: %v", err)
+	}
+	if !mat.Equal(d.X(), X) {
+		t.Errorf("expected X\n%v\ngot\n%v", mat.Formatted(X), mat.Formatted(d.X()))
+	}
+	if len(d.Examples[0].Labels) != 0 {
+		t.Errorf("expected no labels; got %v", d.Examples[0].Labels)
+	}
+
+	if d, _ := Read(strings.NewReader(out), 0); d.NumFeatures != 3 {
+		t.Errorf("expected 3 inferred features; got %d", d.NumFeatures)
+	}
+	if _, err := Read(strings.NewReader(out), 2); err == nil {
+		t.Errorf("expected error for index out of range")
+	}
+}
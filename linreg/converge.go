@@ -0,0 +1,166 @@
+package linreg
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+
+	"github.com/campoy/goml/schedule"
+)
+
+// Convergence defines when GradientDescentContext stops before taking all
+// its steps. Zero values disable each of the criteria.
+type Convergence struct {
+	// CostTol stops when the cost changes by at most CostTol in a step.
+	CostTol float64
+	// GradTol stops when the norm of the gradient is at most GradTol,
+	// before taking the step.
+	GradTol float64
+	// MaxIncrease considers that gradient descent diverged when the cost
+	// grows over MaxIncrease times the initial cost, or times the cost of
+	// predicting zero for every example if it is larger, so starting from
+	// a perfect fit does not make any increase a divergence. A cost that
+	// is NaN or infinite is always considered a divergence.
+	MaxIncrease float64
+}
+
+// diverged reports whether cost is a divergence, given the initial cost and
+// the one of predicting zero for every example.
+func (c Convergence) diverged(cost, initial, zero float64) bool {
+	if math.IsNaN(cost) || math.IsInf(cost, 0) {
+		return true
+	}
+	return c.MaxIncrease > 0 && cost > c.MaxIncrease*math.Max(initial, zero)
+}
+
+// A StopReason tells why GradientDescentContext stopped.
+type StopReason int
+
+const (
+	// MaxIters means all the requested steps were taken.
+	MaxIters StopReason = iota
+	// CostConverged means the cost changed by less than CostTol.
+	CostConverged
+	// GradientConverged means the norm of the gradient was under GradTol.
+	GradientConverged
+	// Canceled means the context was canceled or its deadline passed.
+	Canceled
+	// Diverged means the cost became too large, as a DivergenceError reports.
+	Diverged
+)
+
+func (r StopReason) String() string {
+	switch r {
+	case MaxIters:
+		return "maximum number of iterations"
+	case CostConverged:
+		return "cost converged"
+	case GradientConverged:
+		return "gradient converged"
+	case Canceled:
+		return "canceled"
+	case Diverged:
+		return "diverged"
+	}
+	return fmt.Sprintf("StopReason(%d)", int(r))
+}
+
+// A DivergenceError is returned when the cost of gradient descent
+// becomes too large, which usually means alpha is too large.
+type DivergenceError struct {
+	Iter int     // Step at which the divergence was detected.
+	Cost float64 // Cost after that step.
+}
+
+func (e DivergenceError) Error() string {
+	return fmt.Sprintf("gradient descent diverged at step %d with cost %v", e.Iter, e.Cost)
+}
+
+// GradientDescentContext works like GradientDescentReg, but stops before
+// taking iters steps when any of the criteria in conv is met or when ctx is
+// done, and it returns the reason why it stopped.
+//
+// When the cost diverges it returns a DivergenceError, and theta keeps the
+// values it had before the step that diverged. When ctx is done it returns
+// the error of ctx together with the theta learned so far.
+func GradientDescentContext(ctx context.Context, X, y, theta *mat.Dense, alpha, lambda float64, iters int, conv Convergence, hist *History) (*mat.Dense, [][]float64, []float64, StopReason, error) {
+	return GradientDescentSchedule(ctx, X, y, theta, schedule.Constant(alpha), lambda, iters, conv, hist)
+}
+
+// GradientDescentSchedule works like GradientDescentContext, but the learning
+// rate of each step is given by sched.
+func GradientDescentSchedule(ctx context.Context, X, y, theta *mat.Dense, sched schedule.Schedule, lambda float64, iters int, conv Convergence, hist *History) (*mat.Dense, [][]float64, []float64, StopReason, error) {
+	return descend(ctx, theta, objective{
+		cost: func(theta *mat.Dense) float64 { return ComputeCostReg(X, y, theta, lambda) },
+		grad: func(theta *mat.Dense) *mat.Dense { return gradient(X, y, theta, lambda) },
+	}, sched, iters, conv, hist)
+}
+
+// An objective is a cost minimized by descend, and its gradient.
+type objective struct {
+	cost func(theta *mat.Dense) float64
+	grad func(theta *mat.Dense) *mat.Dense
+}
+
+// descend is the loop shared by all the gradient descent functions that use
+// the whole dataset on every step. It works like GradientDescentSchedule, but
+// minimizes obj.
+func descend(ctx context.Context, theta *mat.Dense, obj objective, sched schedule.Schedule, iters int, conv Convergence, hist *History) (*mat.Dense, [][]float64, []float64, StopReason, error) {
+	if hist == nil {
+		hist = new(History)
+	}
+
+	initial := obj.cost(theta)
+	r, c := theta.Dims()
+	zero := obj.cost(mat.NewDense(r, c, nil))
+	prevCost := initial
+	prev := new(mat.Dense)
+
+	for i := 0; i < iters; i++ {
+		if err := ctx.Err(); err != nil {
+			return theta, hist.Thetas(), hist.Costs(), Canceled, err
+		}
+
+		grad := obj.grad(theta)
+		if conv.GradTol > 0 && mat.Norm(grad, 2) <= conv.GradTol {
+			return theta, hist.Thetas(), hist.Costs(), GradientConverged, nil
+		}
+		prev.CloneFrom(theta)
+		grad.Scale(sched.Rate(i), grad)
+		theta.Sub(theta, grad)
+
+		// The cost is only computed when it is needed, and otherwise theta
+		// is checked to be finite to detect divergences.
+		cost := math.NaN()
+		if hist.due() || conv.CostTol > 0 || conv.MaxIncrease > 0 || !finite(theta) {
+			cost = obj.cost(theta)
+			if conv.diverged(cost, initial, zero) {
+				theta.Copy(prev)
+				return theta, hist.Thetas(), hist.Costs(), Diverged, DivergenceError{Iter: i, Cost: cost}
+			}
+		}
+		hist.Record(theta, cost)
+
+		if conv.CostTol > 0 && math.Abs(prevCost-cost) <= conv.CostTol {
+			return theta, hist.Thetas(), hist.Costs(), CostConverged, nil
+		}
+		prevCost = cost
+	}
+
+	return theta, hist.Thetas(), hist.Costs(), MaxIters, nil
+}
+
+// finite reports whether all the values of m are finite.
+func finite(m *mat.Dense) bool {
+	r, _ := m.Dims()
+	for i := 0; i < r; i++ {
+		for _, v := range m.RawRowView(i) {
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,89 @@
+package linreg
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/campoy/goml/datagen"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestGradientDescentContext(t *testing.T) {
+	X, y, theta := InitParameters(datagen.Linear(50, []float64{1, 2}, 0.1, 0, 1))
+
+	expired, cancel := context.WithTimeout(context.Background(), -time.Second)
+	defer cancel()
+
+	tc := []struct {
+		name   string
+		ctx    context.Context
+		alpha  float64
+		conv   Convergence
+		reason StopReason
+		err    bool
+	}{
+		{"max iters", context.Background(), 0.1, Convergence{}, MaxIters, false},
+		{"cost", context.Background(), 0.1, Convergence{CostTol: 1e-6}, CostConverged, false},
+		{"gradient", context.Background(), 0.1, Convergence{GradTol: 1e-3}, GradientConverged, false},
+		{"canceled", expired, 0.1, Convergence{}, Canceled, true},
+		{"overflow", context.Background(), 1e160, Convergence{}, Diverged, true},
+		{"increase", context.Background(), 3, Convergence{MaxIncrease: 10}, Diverged, true},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			got, thetas, costs, reason, err := GradientDescentContext(tt.ctx, X, y, mat.DenseCopyOf(theta), tt.alpha, 0, 200, tt.conv, nil)
+			if reason != tt.reason {
+				t.Errorf("expected reason %v; got %v", tt.reason, reason)
+			}
+			if tt.err != (err != nil) {
+				t.Errorf("expected error %v; got %v", tt.err, err)
+			}
+			if len(thetas) != len(costs) {
+				t.Errorf("expected as many thetas as costs; got %d and %d", len(thetas), len(costs))
+			}
+			if reason == MaxIters && len(costs) != 200 {
+				t.Errorf("expected 200 steps; got %d", len(costs))
+			}
+			if (reason == CostConverged || reason == GradientConverged) && len(costs) >= 200 {
+				t.Errorf("expected to stop early; got %d steps", len(costs))
+			}
+			if reason == Diverged {
+				if _, ok := err.(DivergenceError); !ok {
+					t.Errorf("expected a DivergenceError; got %T", err)
+				}
+			}
+			for i := 0; i < 2; i++ {
+				if v := got.At(i, 0); math.IsNaN(v) || math.IsInf(v, 0) {
+					t.Errorf("expected theta[%d] to be finite; got %v", i, v)
+				}
+			}
+		})
+	}
+}
+
+func TestDiverged(t *testing.T) {
+	conv := Convergence{MaxIncrease: 10}
+	tc := []struct {
+		name                string
+		cost, initial, zero float64
+		diverged            bool
+	}{
+		{"decreasing", 1, 2, 2, false},
+		{"increase", 30, 2, 2, true},
+		{"perfect start", 1e-20, 0, 2, false},
+		{"perfect start increase", 30, 0, 2, true},
+		{"all zeros", 0, 0, 0, false},
+		{"nan", math.NaN(), 2, 2, true},
+		{"infinite", math.Inf(1), 2, 2, true},
+	}
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := conv.diverged(tt.cost, tt.initial, tt.zero); got != tt.diverged {
+				t.Errorf("expected diverged to be %v; got %v", tt.diverged, got)
+			}
+		})
+	}
+}
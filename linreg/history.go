@@ -0,0 +1,87 @@
+package linreg
+
+import "gonum.org/v1/gonum/mat"
+
+// A History records the values of every parameter and the cost after the
+// steps of gradient descent. Its zero value records every step.
+//
+// For long runs, Every and Limit bound the memory it uses:
+//
+//	h := &linreg.History{Every: 10, Limit: 1000}
+//
+// records one of every ten steps, and only the last thousand of those.
+type History struct {
+	// Every records only one of every Every steps, starting with the first.
+	// Values under 2 record every step.
+	Every int
+	// Limit keeps only the last Limit records, discarding older ones.
+	// A value of 0 keeps all of them.
+	Limit int
+
+	n      int // Number of steps seen by Record.
+	next   int // Position of the oldest record once the buffer is full.
+	steps  []int
+	thetas [][]float64
+	costs  []float64
+}
+
+// Record adds the values of theta and the cost after a new step, unless the
+// step is skipped because of Every. Theta is copied in row-major order, so
+// every column of theta is recorded when it has more than one.
+func (h *History) Record(theta *mat.Dense, cost float64) {
+	step := h.n
+	if !h.due() {
+		h.n++
+		return
+	}
+	h.n++
+
+	r, c := theta.Dims()
+	values := make([]float64, 0, r*c)
+	for i := 0; i < r; i++ {
+		values = append(values, theta.RawRowView(i)...)
+	}
+
+	if h.Limit > 0 && len(h.steps) == h.Limit {
+		h.steps[h.next], h.thetas[h.next], h.costs[h.next] = step, values, cost
+		h.next = (h.next + 1) % h.Limit
+		return
+	}
+	h.steps = append(h.steps, step)
+	h.thetas = append(h.thetas, values)
+	h.costs = append(h.costs, cost)
+}
+
+// due reports whether the values given to the next call to Record are kept,
+// so the cost of the steps that are skipped need not be computed.
+func (h *History) due() bool { return h.Every <= 1 || h.n%h.Every == 0 }
+
+// Len returns the number of records kept.
+func (h *History) Len() int { return len(h.steps) }
+
+// Seen returns the number of steps given to Record, including skipped ones.
+func (h *History) Seen() int { return h.n }
+
+// Steps returns the step number, starting at 0, of each record.
+func (h *History) Steps() []int { return ordered(h, h.steps) }
+
+// Thetas returns the values of theta of each record, from oldest to newest.
+func (h *History) Thetas() [][]float64 { return ordered(h, h.thetas) }
+
+// Costs returns the cost of each record, from oldest to newest.
+func (h *History) Costs() []float64 { return ordered(h, h.costs) }
+
+// Param returns the value of the jth parameter of theta for each record.
+func (h *History) Param(j int) []float64 {
+	thetas := h.Thetas()
+	values := make([]float64, len(thetas))
+	for i, theta := range thetas {
+		values[i] = theta[j]
+	}
+	return values
+}
+
+// ordered returns a copy of the records in s, from oldest to newest.
+func ordered[T any](h *History, s []T) []T {
+	return append(append([]T(nil), s[h.next:]...), s[:h.next]...)
+}
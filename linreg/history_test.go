@@ -0,0 +1,75 @@
+package linreg
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestHistory(t *testing.T) {
+	tc := []struct {
+		name  string
+		h     History
+		steps []int
+	}{
+		{"all", History{}, []int{0, 1, 2, 3, 4, 5, 6}},
+		{"thinned", History{Every: 3}, []int{0, 3, 6}},
+		{"limited", History{Limit: 3}, []int{4, 5, 6}},
+		{"thinned and limited", History{Every: 2, Limit: 3}, []int{2, 4, 6}},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 7; i++ {
+				v := float64(i)
+				tt.h.Record(mat.NewDense(3, 1, []float64{v, 10 * v, 100 * v}), -v)
+			}
+			if got := tt.h.Steps(); !reflect.DeepEqual(got, tt.steps) {
+				t.Fatalf("expected steps %v; got %v", tt.steps, got)
+			}
+			if got := tt.h.Seen(); got != 7 {
+				t.Errorf("expected 7 steps seen; got %d", got)
+			}
+			thetas, costs := tt.h.Thetas(), tt.h.Costs()
+			for i, step := range tt.steps {
+				v := float64(step)
+				if want := []float64{v, 10 * v, 100 * v}; !reflect.DeepEqual(thetas[i], want) {
+					t.Errorf("expected theta %v at step %d; got %v", want, step, thetas[i])
+				}
+				if costs[i] != -v {
+					t.Errorf("expected cost %v at step %d; got %v", -v, step, costs[i])
+				}
+				if got := tt.h.Param(2)[i]; got != 100*v {
+					t.Errorf("expected third parameter %v at step %d; got %v", 100*v, step, got)
+				}
+			}
+		})
+	}
+}
+
+func TestGradientDescentHistory(t *testing.T) {
+	X := mat.NewDense(3, 3, []float64{1, 0, 1, 1, 1, 0, 1, 2, 2})
+	y := mat.NewDense(3, 1, []float64{1, 2, 5})
+	_, thetas, costs := GradientDescent(X, y, mat.NewDense(3, 1, nil), 0.1, 5)
+	if len(thetas) != 5 || len(costs) != 5 {
+		t.Fatalf("expected 5 steps; got %d thetas and %d costs", len(thetas), len(costs))
+	}
+	for i, theta := range thetas {
+		if len(theta) != 3 {
+			t.Errorf("expected 3 parameters at step %d; got %v", i, theta)
+		}
+	}
+
+	hist := &History{Every: 2}
+	_, thetas, costs = GradientDescentWithHistory(X, y, mat.NewDense(3, 1, nil), 0.1, 5, hist)
+	if steps := hist.Steps(); len(steps) != 3 || steps[2] != 4 || hist.Seen() != 5 {
+		t.Fatalf("expected steps 0, 2 and 4 of 5; got %v of %d", steps, hist.Seen())
+	}
+	for i, theta := range thetas {
+		if want := ComputeCost(X, y, mat.NewDense(3, 1, theta)); math.Abs(costs[i]-want) > 1e-12 {
+			t.Errorf("expected cost %v for record %d; got %v", want, i, costs[i])
+		}
+	}
+}
@@ -0,0 +1,271 @@
+package linreg
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// An Inference contains the classical statistics of an ordinary least squares
+// fit, assuming independent errors with a normal distribution and the same
+// variance for all examples.
+type Inference struct {
+	// Names of the coefficients, starting with the intercept.
+	Names []string
+	// Theta holds the fitted coefficients, as computed by NormalEquation.
+	Theta []float64
+	// StdErr, T and P hold the standard error, t statistic and two-sided
+	// p-value of the null hypothesis of each coefficient being zero.
+	StdErr, T, P []float64
+
+	// Residuals holds the differences between the targets and the fit.
+	Residuals []float64
+	// DF is the number of residual degrees of freedom.
+	DF int
+	// Sigma is the residual standard error, the estimated standard
+	// deviation of the errors.
+	Sigma float64
+	// R2 and AdjustedR2 are the fraction of the variance of y explained.
+	R2, AdjustedR2 float64
+	// F is the statistic of the null hypothesis of all the coefficients but
+	// the intercept being zero, and FP is its p-value.
+	F, FP float64
+
+	// cov is the covariance matrix of the coefficients.
+	cov *mat.SymDense
+}
+
+// Infer fits theta with NormalEquation for the given X and y, as returned by
+// InitParameters, and computes its statistics. The first column of X must be
+// the column of ones for the intercept. Coefficients are named after names,
+// which does not include the intercept, or x1, x2, and so on if it is nil.
+//
+// X must have full column rank and more rows than columns, so the standard
+// errors of all the coefficients can be estimated. Infer also fails when y is
+// constant, which leaves no variance to explain, and when X fits y exactly to
+// machine precision, which leaves no residual variance to estimate the
+// standard errors with.
+func Infer(X, y *mat.Dense, names []string) (*Inference, error) {
+	m, n := X.Dims()
+	if _, k := y.Dims(); k != 1 {
+		return nil, errors.Errorf("expected a single column of targets, got %d", k)
+	}
+	if m <= n {
+		return nil, errors.Errorf("need more examples than coefficients, got %d examples for %d coefficients", m, n)
+	}
+
+	var svd mat.SVD
+	if ok := svd.Factorize(X, mat.SVDThin); !ok {
+		return nil, errors.New("could not factorize X")
+	}
+	s := svd.Values(nil)
+	if rank(&svd, m, n) < n {
+		return nil, errors.New("X is rank deficient, some features are linear combinations of others")
+	}
+
+	// X has full rank, so this is the solution NormalEquation returns.
+	theta := new(mat.Dense)
+	svd.SolveTo(theta, y, n)
+
+	// (X'X)^-1 = V S^-2 V'
+	var v mat.Dense
+	svd.VTo(&v)
+	for j := 0; j < n; j++ {
+		col := v.ColView(j).(*mat.VecDense)
+		col.ScaleVec(1/s[j], col)
+	}
+	xtxInv := mat.NewSymDense(n, nil)
+	xtxInv.SymOuterK(1, &v)
+
+	inf := &Inference{Theta: mat.Col(nil, 0, theta), DF: m - n}
+	inf.Names = append([]string{"(Intercept)"}, names...)
+	for j := len(inf.Names); j < n; j++ {
+		inf.Names = append(inf.Names, fmt.Sprintf("x%d", j))
+	}
+
+	fitted := new(mat.Dense)
+	fitted.Mul(X, theta)
+	ys := mat.Col(nil, 0, y)
+	inf.Residuals = make([]float64, m)
+	rss, tss := 0.0, 0.0
+	mean := stat.Mean(ys, nil)
+	for i, v := range ys {
+		inf.Residuals[i] = v - fitted.At(i, 0)
+		rss += inf.Residuals[i] * inf.Residuals[i]
+		tss += (v - mean) * (v - mean)
+	}
+	if tss == 0 {
+		return nil, errors.New("y is constant, there is no variance to explain")
+	}
+	if rss/tss < 0x1p-52 {
+		return nil, errors.New("X fits y exactly, the errors have no variance to estimate")
+	}
+	sigma2 := rss / float64(inf.DF)
+	inf.Sigma = math.Sqrt(sigma2)
+	inf.R2 = 1 - rss/tss
+	inf.AdjustedR2 = 1 - (1-inf.R2)*float64(m-1)/float64(inf.DF)
+	if n > 1 {
+		inf.F = (tss - rss) / float64(n-1) / sigma2
+		inf.FP = distuv.F{D1: float64(n - 1), D2: float64(inf.DF)}.Survival(inf.F)
+	}
+
+	inf.cov = mat.NewSymDense(n, nil)
+	inf.cov.ScaleSym(sigma2, xtxInv)
+	inf.coefStats()
+	return inf, nil
+}
+
+// coefStats computes the standard errors, t statistics and p-values
+// of the coefficients from their covariance matrix.
+func (inf *Inference) coefStats() {
+	n := len(inf.Theta)
+	inf.StdErr, inf.T, inf.P = make([]float64, n), make([]float64, n), make([]float64, n)
+	t := distuv.StudentsT{Mu: 0, Sigma: 1, Nu: float64(inf.DF)}
+	for j := range inf.Theta {
+		inf.StdErr[j] = math.Sqrt(inf.cov.At(j, j))
+		inf.T[j] = inf.Theta[j] / inf.StdErr[j]
+		inf.P[j] = 2 * t.Survival(math.Abs(inf.T[j]))
+	}
+}
+
+// tQuantile returns the quantile of the t distribution used for two-sided
+// intervals with the given confidence level, such as 0.95.
+func (inf *Inference) tQuantile(level float64) float64 {
+	return distuv.StudentsT{Mu: 0, Sigma: 1, Nu: float64(inf.DF)}.Quantile(1 - (1-level)/2)
+}
+
+// ConfidenceIntervals returns the lower and upper bounds of the confidence
+// intervals of the coefficients with the given level, such as 0.95.
+func (inf *Inference) ConfidenceIntervals(level float64) (lo, hi []float64) {
+	q := inf.tQuantile(level)
+	lo, hi = make([]float64, len(inf.Theta)), make([]float64, len(inf.Theta))
+	for j, v := range inf.Theta {
+		lo[j], hi[j] = v-q*inf.StdErr[j], v+q*inf.StdErr[j]
+	}
+	return lo, hi
+}
+
+// PredictionIntervals returns the predictions for the rows of X, which must
+// have the same columns as the X given to Infer, together with the lower and
+// upper bounds of the intervals where new observations will be with the given
+// probability, such as 0.95.
+func (inf *Inference) PredictionIntervals(X mat.Matrix, level float64) (pred, lo, hi []float64, err error) {
+	r, c := X.Dims()
+	if c != len(inf.Theta) {
+		return nil, nil, nil, errors.Errorf("expected %d columns, got %d", len(inf.Theta), c)
+	}
+	q := inf.tQuantile(level)
+	pred, lo, hi = make([]float64, r), make([]float64, r), make([]float64, r)
+	x := mat.NewVecDense(c, nil)
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			x.SetVec(j, X.At(i, j))
+		}
+		pred[i] = mat.Dot(x, mat.NewVecDense(c, inf.Theta))
+		// The variance of a new observation is the one of the fit at x,
+		// plus the one of the error.
+		se := math.Sqrt(mat.Inner(x, inf.cov, x) + inf.Sigma*inf.Sigma)
+		lo[i], hi[i] = pred[i]-q*se, pred[i]+q*se
+	}
+	return pred, lo, hi, nil
+}
+
+// Unnormalize returns the statistics of the coefficients for the original
+// features, given the means and standard deviations returned by the
+// NormalizeFeatures call applied to the X given to Infer. The fit itself
+// does not change, so only the intercept has different statistics, and the
+// PredictionIntervals of the result take rows of original features.
+func (inf *Inference) Unnormalize(means, stdDevs *mat.Dense) *Inference {
+	n := len(inf.Theta)
+	// The coefficients for the original features are A theta.
+	A := mat.NewDense(n, n, nil)
+	A.Set(0, 0, 1)
+	for j := 1; j < n; j++ {
+		A.Set(0, j, -means.At(0, j)/stdDevs.At(0, j))
+		A.Set(j, j, 1/stdDevs.At(0, j))
+	}
+
+	out := *inf
+	theta := mat.NewVecDense(n, nil)
+	theta.MulVec(A, mat.NewVecDense(n, inf.Theta))
+	out.Theta = theta.RawVector().Data
+
+	cov := new(mat.Dense)
+	cov.Product(A, inf.cov, A.T())
+	out.cov = mat.NewSymDense(n, nil)
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			out.cov.SetSym(i, j, cov.At(i, j))
+		}
+	}
+	out.coefStats()
+	return &out
+}
+
+// String returns a summary of the fit, similar to the one of R's summary(lm).
+func (inf *Inference) String() string {
+	var buf bytes.Buffer
+	res := append([]float64(nil), inf.Residuals...)
+	sort.Float64s(res)
+	w := tabwriter.NewWriter(&buf, 0, 4, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "Residuals:")
+	fmt.Fprintln(w, "Min\t1Q\tMedian\t3Q\tMax\t")
+	for _, p := range []float64{0, 0.25, 0.5, 0.75, 1} {
+		fmt.Fprintf(w, "%.4g\t", stat.Quantile(p, stat.Empirical, res, nil))
+	}
+	fmt.Fprintln(w)
+	w.Flush()
+
+	w = tabwriter.NewWriter(&buf, 0, 4, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(&buf, "\nCoefficients:")
+	fmt.Fprintln(w, "\tEstimate\tStd. Error\tt value\tPr(>|t|)\t\t")
+	width := 0
+	for _, name := range inf.Names {
+		width = max(width, len(name))
+	}
+	for j, name := range inf.Names {
+		// Names are padded to be aligned to the left.
+		fmt.Fprintf(w, "%-*s\t%.4g\t%.4g\t%.3f\t%s\t%s\t\n",
+			width, name, inf.Theta[j], inf.StdErr[j], inf.T[j], formatP(inf.P[j]), stars(inf.P[j]))
+	}
+	w.Flush()
+	fmt.Fprintln(&buf, "---")
+	fmt.Fprintln(&buf, "Signif. codes:  0 '***' 0.001 '**' 0.01 '*' 0.05 '.' 0.1 ' ' 1")
+	fmt.Fprintln(&buf)
+	fmt.Fprintf(&buf, "Residual standard error: %.4g on %d degrees of freedom\n", inf.Sigma, inf.DF)
+	fmt.Fprintf(&buf, "Multiple R-squared:  %.4f,\tAdjusted R-squared:  %.4f\n", inf.R2, inf.AdjustedR2)
+	if n := len(inf.Theta); n > 1 {
+		fmt.Fprintf(&buf, "F-statistic: %.4g on %d and %d DF,  p-value: %s\n", inf.F, n-1, inf.DF, formatP(inf.FP))
+	}
+	return buf.String()
+}
+
+// formatP formats a p-value, showing the smallest ones as a bound.
+func formatP(p float64) string {
+	if p < 2.2e-16 {
+		return "< 2.2e-16"
+	}
+	return fmt.Sprintf("%.3g", p)
+}
+
+// stars returns the significance code of a p-value.
+func stars(p float64) string {
+	switch {
+	case p < 0.001:
+		return "***"
+	case p < 0.01:
+		return "**"
+	case p < 0.05:
+		return "*"
+	case p < 0.1:
+		return "."
+	}
+	return ""
+}
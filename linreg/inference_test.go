@@ -0,0 +1,137 @@
+package linreg
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestInfer(t *testing.T) {
+	data := mat.NewDense(5, 2, []float64{1, 2, 2, 4, 3, 5, 4, 4, 5, 5})
+	X, y, _ := InitParameters(data)
+
+	inf, err := Infer(X, y, []string{"x"})
+	if err != nil {
+		t.Fatalf("could not infer: %v", err)
+	}
+
+	// Values of the simple regression formulas: the residual sum of squares
+	// is 2.4 on 3 degrees of freedom, and the sum of squares of x is 10.
+	tc := []struct {
+		name string
+		got  float64
+		want float64
+	}{
+		{"intercept", inf.Theta[0], 2.2},
+		{"slope", inf.Theta[1], 0.6},
+		{"intercept std err", inf.StdErr[0], math.Sqrt(0.8 * (0.2 + 0.9))},
+		{"slope std err", inf.StdErr[1], math.Sqrt(0.08)},
+		{"slope t", inf.T[1], 0.6 / math.Sqrt(0.08)},
+		{"slope p", inf.P[1], 0.1240},
+		{"sigma", inf.Sigma, math.Sqrt(0.8)},
+		{"r2", inf.R2, 0.6},
+		{"adjusted r2", inf.AdjustedR2, 1 - 0.4*4/3},
+		{"f", inf.F, 4.5},
+		{"f p", inf.FP, inf.P[1]},
+	}
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			if math.Abs(tt.got-tt.want) > 1e-4 {
+				t.Errorf("expected %v; got %v", tt.want, tt.got)
+			}
+		})
+	}
+
+	lo, hi := inf.ConfidenceIntervals(0.95)
+	if math.Abs(lo[1]-(0.6-3.182446*math.Sqrt(0.08))) > 1e-5 || math.Abs(hi[1]-(0.6+3.182446*math.Sqrt(0.08))) > 1e-5 {
+		t.Errorf("expected slope interval around 0.6 +/- 0.9; got [%v, %v]", lo[1], hi[1])
+	}
+
+	pred, plo, phi, err := inf.PredictionIntervals(mat.NewDense(1, 2, []float64{1, 3}), 0.95)
+	if err != nil {
+		t.Fatalf("could not compute prediction intervals: %v", err)
+	}
+	if half := 3.182446 * math.Sqrt(0.96); math.Abs(pred[0]-4) > 1e-9 || math.Abs(phi[0]-pred[0]-half) > 1e-5 || math.Abs(pred[0]-plo[0]-half) > 1e-5 {
+		t.Errorf("expected prediction 4 +/- %v; got %v in [%v, %v]", half, pred[0], plo[0], phi[0])
+	}
+
+	s := inf.String()
+	for _, want := range []string{"(Intercept)", "Residual standard error: 0.8944 on 3 degrees of freedom", "F-statistic: 4.5 on 1 and 3 DF"} {
+		if !strings.Contains(s, want) {
+			t.Errorf("expected summary to contain %q; got:\n%s", want, s)
+		}
+	}
+}
+
+func TestInferUnnormalize(t *testing.T) {
+	data := mat.NewDense(6, 3, []float64{
+		1, 10, 3,
+		2, 30, 6,
+		3, 20, 6,
+		4, 50, 11,
+		5, 40, 10,
+		6, 70, 15,
+	})
+	X, y, _ := InitParameters(data)
+	raw, err := Infer(X, y, nil)
+	if err != nil {
+		t.Fatalf("could not infer: %v", err)
+	}
+
+	means, stdDevs := NormalizeFeatures(X)
+	norm, err := Infer(X, y, nil)
+	if err != nil {
+		t.Fatalf("could not infer: %v", err)
+	}
+	got := norm.Unnormalize(means, stdDevs)
+	for j := range raw.Theta {
+		if math.Abs(got.Theta[j]-raw.Theta[j]) > 1e-9 {
+			t.Errorf("expected theta[%d] to be %v; got %v", j, raw.Theta[j], got.Theta[j])
+		}
+		if math.Abs(got.StdErr[j]-raw.StdErr[j]) > 1e-9 {
+			t.Errorf("expected std err %d to be %v; got %v", j, raw.StdErr[j], got.StdErr[j])
+		}
+		if math.Abs(got.P[j]-raw.P[j]) > 1e-9 {
+			t.Errorf("expected p-value %d to be %v; got %v", j, raw.P[j], got.P[j])
+		}
+	}
+
+	X.SetCol(2, mat.Col(nil, 1, X))
+	if _, err := Infer(X, y, nil); err == nil {
+		t.Errorf("expected error for rank deficient X")
+	}
+
+	// The third feature is a combination of the others up to rounding,
+	// and y has some noise so the fit is not exact.
+	data = mat.NewDense(6, 4, collinear(
+		[]float64{7.8, 5.7, 2.2, 8.3, 0.1, 8.8},
+		[]float64{9.1, 8.3, 7.9, 1.8, 0.1, 3.2},
+	))
+	for i, e := range []float64{0.1, -0.2, 0.3, 0.1, -0.1, -0.2} {
+		data.Set(i, 3, data.At(i, 3)+e)
+	}
+	X, y, _ = InitParameters(data)
+	if inf, err := Infer(X, y, nil); err == nil {
+		t.Errorf("expected error for numerically rank deficient X; got:\n%v", inf)
+	}
+}
+
+func TestInferDegenerate(t *testing.T) {
+	tc := []struct {
+		name string
+		data []float64
+	}{
+		{"constant y", []float64{1, 3, 2, 3, 3, 3, 4, 3}},
+		{"exact fit", []float64{1, 3, 2, 5, 3, 7, 4, 9}},
+	}
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			X, y, _ := InitParameters(mat.NewDense(4, 2, tt.data))
+			if inf, err := Infer(X, y, nil); err == nil {
+				t.Errorf("expected error; got:\n%v", inf)
+			}
+		})
+	}
+}
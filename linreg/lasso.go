@@ -0,0 +1,195 @@
+package linreg
+
+import (
+	"math"
+
+	"github.com/pkg/errors"
+	"gonum.org/v1/gonum/mat"
+)
+
+// ComputeCostElasticNet computes the elastic-net cost of using theta as the
+// parameter for linear regression to fit the data points in X and y:
+//
+//	ComputeCost(X, y, theta) + lambda * (l1Ratio*|theta|_1 + (1-l1Ratio)/2*|theta|_2^2)
+//
+// where the norms do not include the intercept, the first row of theta.
+// An l1Ratio of 1 gives the lasso, and 0 gives ridge regression with the
+// penalty of ComputeCostReg for a lambda m times larger.
+func ComputeCostElasticNet(X, y, theta *mat.Dense, lambda, l1Ratio float64) float64 {
+	cost := ComputeCost(X, y, theta)
+	r, c := theta.Dims()
+	l1, l2 := 0.0, 0.0
+	for i := 1; i < r; i++ {
+		for j := 0; j < c; j++ {
+			v := theta.At(i, j)
+			l1 += math.Abs(v)
+			l2 += v * v
+		}
+	}
+	return cost + lambda*(l1Ratio*l1+(1-l1Ratio)*l2/2)
+}
+
+// ElasticNet minimizes ComputeCostElasticNet by coordinate descent, starting
+// from the given theta, which is updated in place and returned. Starting from
+// the solution for a close lambda, as ElasticNetPath does, takes fewer sweeps
+// than starting from zeros.
+//
+// Every sweep updates each coefficient in turn, and it stops after iters sweeps
+// or once no coefficient changes by more than tol in a sweep. It returns the
+// number of sweeps performed. Features should be normalized first, so the
+// penalty is comparable across coefficients.
+func ElasticNet(X, y, theta *mat.Dense, lambda, l1Ratio, tol float64, iters int) (*mat.Dense, int) {
+	m, n := X.Dims()
+	_, k := y.Dims()
+
+	// Mean of the squared values of each column.
+	norms := make([]float64, n)
+	for j := range norms {
+		col := mat.Col(nil, j, X)
+		norms[j] = mat.Dot(mat.NewVecDense(m, col), mat.NewVecDense(m, col)) / float64(m)
+	}
+
+	sweeps := 0
+	for c := 0; c < k; c++ {
+		// r holds the residuals y - X*theta for column c.
+		r := mat.NewVecDense(m, nil)
+		r.MulVec(X, theta.ColView(c))
+		r.SubVec(y.ColView(c), r)
+
+		for s := 1; s <= iters; s++ {
+			maxDelta := 0.0
+			for j := 0; j < n; j++ {
+				old := theta.At(j, c)
+				next := 0.0
+				if norms[j] > 0 {
+					rho := old * norms[j]
+					for i := 0; i < m; i++ {
+						rho += X.At(i, j) * r.AtVec(i) / float64(m)
+					}
+					if j == 0 {
+						next = rho / norms[j]
+					} else {
+						next = softThreshold(rho, lambda*l1Ratio) / (norms[j] + lambda*(1-l1Ratio))
+					}
+				}
+				if delta := next - old; delta != 0 {
+					for i := 0; i < m; i++ {
+						r.SetVec(i, r.AtVec(i)-delta*X.At(i, j))
+					}
+					theta.Set(j, c, next)
+					maxDelta = math.Max(maxDelta, math.Abs(delta))
+				}
+			}
+			sweeps = max(sweeps, s)
+			if maxDelta <= tol {
+				break
+			}
+		}
+	}
+	return theta, sweeps
+}
+
+// Lasso minimizes ComputeCostElasticNet with an l1Ratio of 1,
+// as described for ElasticNet.
+func Lasso(X, y, theta *mat.Dense, lambda, tol float64, iters int) (*mat.Dense, int) {
+	return ElasticNet(X, y, theta, lambda, 1, tol, iters)
+}
+
+// softThreshold returns v moved towards zero by t, or zero if |v| <= t.
+func softThreshold(v, t float64) float64 {
+	switch {
+	case v > t:
+		return v - t
+	case v < -t:
+		return v + t
+	}
+	return 0
+}
+
+// LambdaMax returns the smallest lambda for which ElasticNet, with the given
+// l1Ratio, sets every coefficient but the intercept to zero. X is expected to
+// have the column of ones added by InitParameters and normalized features.
+// The l1Ratio must be positive, since no lambda is large enough otherwise.
+func LambdaMax(X, y *mat.Dense, l1Ratio float64) float64 {
+	m, n := X.Dims()
+	_, k := y.Dims()
+	lmax := 0.0
+	for c := 0; c < k; c++ {
+		col := mat.Col(nil, c, y)
+		mean := 0.0
+		for _, v := range col {
+			mean += v / float64(m)
+		}
+		for j := 1; j < n; j++ {
+			dot := 0.0
+			for i, v := range col {
+				dot += X.At(i, j) * (v - mean)
+			}
+			lmax = math.Max(lmax, math.Abs(dot)/float64(m))
+		}
+	}
+	return lmax / l1Ratio
+}
+
+// LambdaPath returns n lambdas decreasing geometrically from LambdaMax down to
+// ratio times LambdaMax, which is the order in which ElasticNetPath benefits
+// the most from warm starts.
+func LambdaPath(X, y *mat.Dense, l1Ratio float64, n int, ratio float64) []float64 {
+	lmax := LambdaMax(X, y, l1Ratio)
+	lambdas := make([]float64, n)
+	for i := range lambdas {
+		t := 0.0
+		if n > 1 {
+			t = float64(i) / float64(n-1)
+		}
+		lambdas[i] = lmax * math.Pow(ratio, t)
+	}
+	return lambdas
+}
+
+// A Path contains the coefficients fitted by ElasticNetPath for each lambda.
+type Path struct {
+	Lambdas []float64
+	// Coefs holds the coefficients fitted with Lambdas[i] in its ith row.
+	Coefs *mat.Dense
+	// Active holds, for each lambda, the indices of the non-zero coefficients,
+	// not including the intercept.
+	Active [][]int
+	// Sweeps holds the number of sweeps of coordinate descent for each lambda.
+	Sweeps []int
+}
+
+// ElasticNetPath fits ElasticNet for each of the given lambdas, using the
+// solution for each lambda as the starting point for the next one. Lambdas
+// should be sorted in decreasing order, as returned by LambdaPath.
+// X and y must have a single column of targets.
+func ElasticNetPath(X, y *mat.Dense, lambdas []float64, l1Ratio, tol float64, iters int) (*Path, error) {
+	if l1Ratio < 0 || l1Ratio > 1 {
+		return nil, errors.Errorf("l1 ratio must be between 0 and 1, got %v", l1Ratio)
+	}
+	if _, k := y.Dims(); k != 1 {
+		return nil, errors.Errorf("expected a single column of targets, got %d", k)
+	}
+	_, n := X.Dims()
+	path := &Path{
+		Lambdas: lambdas,
+		Coefs:   mat.NewDense(len(lambdas), n, nil),
+		Active:  make([][]int, len(lambdas)),
+		Sweeps:  make([]int, len(lambdas)),
+	}
+	theta := mat.NewDense(n, 1, nil)
+	for i, lambda := range lambdas {
+		if lambda < 0 {
+			return nil, errors.Errorf("lambda must not be negative, got %v", lambda)
+		}
+		_, path.Sweeps[i] = ElasticNet(X, y, theta, lambda, l1Ratio, tol, iters)
+		coefs := mat.Col(nil, 0, theta)
+		path.Coefs.SetRow(i, coefs)
+		for j := 1; j < n; j++ {
+			if coefs[j] != 0 {
+				path.Active[i] = append(path.Active[i], j)
+			}
+		}
+	}
+	return path, nil
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/campoy/goml/datagen"
+	"github.com/campoy/goml/iplot"
+	"github.com/campoy/goml/linreg"
+	"github.com/campoy/goml/util"
+)
+
+func main() {
+	plots := flag.String("plots", "", "directory where plots are saved instead of shown")
+	l1Ratio := flag.Float64("l1", 1, "ratio of the L1 penalty, 1 for lasso and less for elastic-net")
+	flag.Parse()
+
+	sink, err := util.NewSink(*plots)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Only 4 of the 20 features have an effect on the target.
+	theta := make([]float64, 21)
+	theta[0], theta[3], theta[7], theta[12], theta[18] = 1, 5, -3, 2, 0.5
+	data := datagen.Linear(100, theta, 0.5, 0, 1)
+
+	X, y, _ := linreg.InitParameters(data)
+	linreg.NormalizeFeatures(X)
+
+	lambdas := linreg.LambdaPath(X, y, *l1Ratio, 30, 0.001)
+	path, err := linreg.ElasticNetPath(X, y, lambdas, *l1Ratio, 1e-6, 1000)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println("Non-zero coefficients for each lambda")
+	for i, lambda := range path.Lambdas {
+		pattern := []byte(strings.Repeat(".", len(theta)-1))
+		for _, j := range path.Active[i] {
+			pattern[j-1] = 'x'
+		}
+		fmt.Printf("%8.4f %s %2d features, %3d sweeps\n", lambda, pattern, len(path.Active[i]), path.Sweeps[i])
+	}
+
+	names := make([]string, len(theta)-1)
+	for j := range names {
+		names[j] = fmt.Sprintf("x%d", j+1)
+	}
+	p, err := iplot.RegularizationPath(lambdas, path.Coefs.Slice(0, len(lambdas), 1, len(theta)), names)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := sink.WritePlot(p, 600, 400); err != nil {
+		log.Fatal(err)
+	}
+}
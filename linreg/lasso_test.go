@@ -0,0 +1,110 @@
+package linreg
+
+import (
+	"math"
+	"testing"
+
+	"github.com/campoy/goml/datagen"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestElasticNet(t *testing.T) {
+	data := mat.NewDense(5, 3, []float64{
+		1, 2, 7,
+		2, 1, 6,
+		3, 4, 15,
+		4, 3, 14,
+		5, 5, 21,
+	})
+	X, y, _ := InitParameters(data)
+	NormalizeFeatures(X)
+	m, n := X.Dims()
+
+	ols, err := NormalEquation(X, y)
+	if err != nil {
+		t.Fatalf("could not solve: %v", err)
+	}
+	// ElasticNet with only the L2 penalty is ridge regression,
+	// with a lambda m times smaller than RidgeEquation.
+	ridge, err := RidgeEquation(X, y, 2*float64(m))
+	if err != nil {
+		t.Fatalf("could not solve: %v", err)
+	}
+
+	tc := []struct {
+		name    string
+		lambda  float64
+		l1Ratio float64
+		want    *mat.Dense
+	}{
+		{"no penalty", 0, 1, ols},
+		{"ridge", 2, 0, ridge},
+	}
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			theta, _ := ElasticNet(X, y, mat.NewDense(n, 1, nil), tt.lambda, tt.l1Ratio, 1e-12, 10000)
+			for i := 0; i < n; i++ {
+				if got, want := theta.At(i, 0), tt.want.At(i, 0); math.Abs(got-want) > 1e-6 {
+					t.Errorf("expected theta[%d] to be %v; got %v", i, want, got)
+				}
+			}
+		})
+	}
+
+	t.Run("lambda max", func(t *testing.T) {
+		lmax := LambdaMax(X, y, 1)
+		theta, _ := Lasso(X, y, mat.NewDense(n, 1, nil), lmax, 1e-12, 1000)
+		if got, want := theta.At(0, 0), 12.6; math.Abs(got-want) > 1e-9 {
+			t.Errorf("expected the intercept to be the mean %v; got %v", want, got)
+		}
+		for i := 1; i < n; i++ {
+			if theta.At(i, 0) != 0 {
+				t.Errorf("expected theta[%d] to be zero; got %v", i, theta.At(i, 0))
+			}
+		}
+		theta, _ = Lasso(X, y, mat.NewDense(n, 1, nil), 0.9*lmax, 1e-12, 1000)
+		if theta.At(1, 0) == 0 && theta.At(2, 0) == 0 {
+			t.Errorf("expected some coefficient under lambda max to be non-zero")
+		}
+	})
+}
+
+func TestElasticNetPath(t *testing.T) {
+	theta := []float64{3, 0, 4, 0, 0, -2, 0, 0, 0, 0, 0}
+	X, y, _ := InitParameters(datagen.Linear(200, theta, 0.1, 0, 1))
+	NormalizeFeatures(X)
+
+	lambdas := LambdaPath(X, y, 1, 20, 0.001)
+	if got, want := lambdas[0], LambdaMax(X, y, 1); got != want {
+		t.Errorf("expected path to start at %v; got %v", want, got)
+	}
+	if got, want := lambdas[len(lambdas)-1], 0.001*lambdas[0]; math.Abs(got-want) > 1e-12 {
+		t.Errorf("expected path to end at %v; got %v", want, got)
+	}
+
+	path, err := ElasticNetPath(X, y, lambdas, 1, 1e-8, 1000)
+	if err != nil {
+		t.Fatalf("could not compute path: %v", err)
+	}
+	if len(path.Active[0]) != 0 {
+		t.Errorf("expected no active coefficients at lambda max; got %v", path.Active[0])
+	}
+	// The first coefficients to become non-zero are the ones with a true effect.
+	for i, active := range path.Active {
+		if len(active) > 2 {
+			break
+		}
+		for _, j := range active {
+			if theta[j] == 0 {
+				t.Errorf("lambda %v: expected only true features to be active; got %v", lambdas[i], active)
+			}
+		}
+	}
+	if got := path.Active[len(lambdas)/2]; len(got) != 2 || got[0] != 2 || got[1] != 5 {
+		t.Errorf("expected features [2 5] to be active at lambda %v; got %v", lambdas[len(lambdas)/2], got)
+	}
+
+	if _, err := ElasticNetPath(X, y, lambdas, 2, 1e-8, 1000); err == nil {
+		t.Errorf("expected error for l1 ratio out of range")
+	}
+}
@@ -18,26 +18,36 @@ func ComputeCost(X, y, theta *mat.Dense) float64 {
 
 // GradientDescent performs gradient descent to learn theta returns the updated
 // theta after taking iters gradient steps with learning rate alpha.
+// It also returns the values of all the parameters in theta after each step,
+// and the cost for each of them.
 func GradientDescent(X, y, theta *mat.Dense, alpha float64, iters int) (*mat.Dense, [][]float64, []float64) {
-	var costs []float64
-	var thetas [][]float64
+	return GradientDescentWithHistory(X, y, theta, alpha, iters, nil)
+}
 
-	m, _ := X.Dims()
-	for i := 0; i < iters; i++ {
-		h := new(mat.Dense)
-		h.Mul(X, theta) // (X * theta - y) * X
-		h.Sub(h, y)
+// GradientDescentWithHistory works like GradientDescent, but returns only the
+// parameters and costs of the steps recorded by hist. A nil hist records
+// every step.
+func GradientDescentWithHistory(X, y, theta *mat.Dense, alpha float64, iters int, hist *History) (*mat.Dense, [][]float64, []float64) {
+	return GradientDescentReg(X, y, theta, alpha, 0, iters, hist)
+}
 
-		grad := new(mat.Dense) // -alpha/m (h . X)
-		grad.Mul(X.T(), h)
-		grad.Scale(alpha/float64(m), grad)
-		theta.Sub(theta, grad)
+// gradient returns the gradient of ComputeCostReg with respect to theta.
+func gradient(X, y, theta *mat.Dense, lambda float64) *mat.Dense {
+	m, _ := X.Dims()
+	h := new(mat.Dense)
+	h.Mul(X, theta) // (X * theta - y) * X
+	h.Sub(h, y)
 
-		costs = append(costs, ComputeCost(X, y, theta))
-		thetas = append(thetas, []float64{theta.At(0, 0), theta.At(1, 0)})
+	grad := new(mat.Dense) // 1/m (h . X) + lambda/m theta
+	grad.Mul(X.T(), h)
+	if lambda != 0 {
+		penalty := mat.DenseCopyOf(theta)
+		penalty.SetRow(0, make([]float64, penalty.RawMatrix().Cols))
+		penalty.Scale(lambda, penalty)
+		grad.Add(grad, penalty)
 	}
-
-	return theta, thetas, costs
+	grad.Scale(1/float64(m), grad)
+	return grad
 }
 
 // InitParameters returns the X, y and theta parameters extracted
@@ -0,0 +1,235 @@
+package linreg
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"gonum.org/v1/gonum/mat"
+
+	"github.com/campoy/goml/util"
+)
+
+// A Model is a linear regression model that works on raw features. It adds
+// the intercept and normalizes the features itself, with the statistics it
+// computed while fitting, so callers do not need to repeat those steps.
+//
+//	var m linreg.Model
+//	if err := m.Fit(X, y); err != nil { ... }
+//	pred, err := m.Predict(mat.NewDense(1, 2, []float64{1650, 3}))
+type Model struct {
+	// Lambda is the ridge regularization parameter used by Fit.
+	Lambda float64
+
+	// Means and StdDevs hold the statistics used to normalize each feature.
+	Means, StdDevs []float64
+	// Theta has the intercept in its first row, followed by a row per
+	// feature, and a column per target.
+	Theta *mat.Dense
+}
+
+// Fit learns the parameters of the model from the raw features in X,
+// with a row per example, and the targets in y, with a column per target.
+// It uses RidgeEquation on the normalized features.
+func (m *Model) Fit(X, y util.Matrix) error {
+	r, n := X.Dims()
+	yr, k := y.Dims()
+	if yr != r {
+		return errors.Errorf("got %d targets for %d examples", yr, r)
+	}
+	if r == 0 || k == 0 {
+		return errors.Errorf("cannot fit %d examples with %d targets", r, k)
+	}
+
+	design := mat.NewDense(r, n+1, nil)
+	for i := 0; i < r; i++ {
+		row := design.RawRowView(i)
+		row[0] = 1
+		for j := 0; j < n; j++ {
+			row[j+1] = X.At(i, j)
+		}
+	}
+	means, stdDevs := NormalizeFeatures(design)
+
+	m.Means = make([]float64, n)
+	m.StdDevs = make([]float64, n)
+	for j := 0; j < n; j++ {
+		m.Means[j], m.StdDevs[j] = means.At(0, j+1), stdDevs.At(0, j+1)
+		// Constant features cannot be scaled, and they carry no information
+		// the intercept does not, so they are only centered.
+		if m.StdDevs[j] == 0 {
+			m.StdDevs[j] = 1
+			for i := 0; i < r; i++ {
+				design.Set(i, j+1, 0)
+			}
+		}
+	}
+
+	theta, err := RidgeEquation(design, util.Dense(y), m.Lambda)
+	if err != nil {
+		return errors.Wrap(err, "could not fit model")
+	}
+	m.Theta = theta
+	return nil
+}
+
+// Predict returns the predictions of the model for the raw features in X,
+// with a row per example and a column per target.
+func (m *Model) Predict(X util.Matrix) (*mat.Dense, error) {
+	if m.Theta == nil {
+		return nil, errors.New("model has not been fitted")
+	}
+	r, n := X.Dims()
+	if n != len(m.Means) {
+		return nil, errors.Errorf("model expects %d features, got %d", len(m.Means), n)
+	}
+
+	design := mat.NewDense(r, n+1, nil)
+	for i := 0; i < r; i++ {
+		row := design.RawRowView(i)
+		row[0] = 1
+		for j := 0; j < n; j++ {
+			row[j+1] = (X.At(i, j) - m.Means[j]) / m.StdDevs[j]
+		}
+	}
+	pred := new(mat.Dense)
+	pred.Mul(design, m.Theta)
+	return pred, nil
+}
+
+// modelJSON is the JSON encoding of a Model.
+type modelJSON struct {
+	Lambda  float64     `json:"lambda"`
+	Means   []float64   `json:"means"`
+	StdDevs []float64   `json:"stdDevs"`
+	Theta   [][]float64 `json:"theta"`
+}
+
+// MarshalJSON encodes the fitted parameters of the model, and Lambda.
+func (m *Model) MarshalJSON() ([]byte, error) {
+	if m.Theta == nil {
+		return nil, errors.New("model has not been fitted")
+	}
+	r, _ := m.Theta.Dims()
+	v := modelJSON{Lambda: m.Lambda, Means: m.Means, StdDevs: m.StdDevs, Theta: make([][]float64, r)}
+	for i := range v.Theta {
+		v.Theta[i] = mat.Row(nil, i, m.Theta)
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON decodes the parameters encoded by MarshalJSON.
+func (m *Model) UnmarshalJSON(data []byte) error {
+	var v modelJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if len(v.StdDevs) != len(v.Means) || len(v.Theta) != len(v.Means)+1 || len(v.Theta[0]) == 0 {
+		return errors.Errorf("inconsistent model with %d means, %d standard deviations and %d rows of theta",
+			len(v.Means), len(v.StdDevs), len(v.Theta))
+	}
+	k := len(v.Theta[0])
+	theta := mat.NewDense(len(v.Theta), k, nil)
+	for i, row := range v.Theta {
+		if len(row) != k {
+			return errors.Errorf("row %d of theta has %d values, expected %d", i, len(row), k)
+		}
+		theta.SetRow(i, row)
+	}
+	m.Lambda, m.Means, m.StdDevs, m.Theta = v.Lambda, v.Means, v.StdDevs, theta
+	return nil
+}
+
+// WriteBinary writes the fitted parameters of the model to w, as the four
+// matrices means, standard deviations, theta and lambda in the format of
+// util.WriteBinary. They can be read back with ReadModel.
+func (m *Model) WriteBinary(w io.Writer) error {
+	if m.Theta == nil {
+		return errors.New("model has not been fitted")
+	}
+	for _, v := range []util.Matrix{vector(m.Means), vector(m.StdDevs), m.Theta, vector{m.Lambda}} {
+		if err := util.WriteBinary(w, v, util.Float64); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// vector is a matrix with a single row, which unlike a *mat.Dense can be empty.
+type vector []float64
+
+func (v vector) Dims() (int, int)    { return 1, len(v) }
+func (v vector) At(i, j int) float64 { return v[j] }
+
+// ReadModel reads a model written by WriteBinary from r.
+func ReadModel(r io.Reader) (*Model, error) {
+	var ms [4][]float64
+	var k int
+	for i := range ms {
+		v, err := util.ReadBinary(r)
+		if err != nil {
+			return nil, err
+		}
+		rows, cols := v.Dims()
+		if i != 2 && rows != 1 {
+			return nil, errors.Errorf("expected a single row of statistics, got %d", rows)
+		}
+		if i == 2 {
+			k = cols
+		}
+		ms[i] = make([]float64, 0, rows*cols)
+		for row := 0; row < rows; row++ {
+			for col := 0; col < cols; col++ {
+				ms[i] = append(ms[i], v.At(row, col))
+			}
+		}
+	}
+	n := len(ms[0])
+	if k == 0 {
+		return nil, errors.New("theta has no columns")
+	}
+	if len(ms[1]) != n || len(ms[2]) != (n+1)*k || len(ms[3]) != 1 {
+		return nil, errors.Errorf("inconsistent model with %d means, %d standard deviations, %d values of theta and %d lambdas",
+			n, len(ms[1]), len(ms[2]), len(ms[3]))
+	}
+	return &Model{Lambda: ms[3][0], Means: ms[0], StdDevs: ms[1], Theta: mat.NewDense(n+1, k, ms[2])}, nil
+}
+
+// Save writes the model to the file at path, encoded as JSON when the path
+// has the .json extension and with WriteBinary otherwise.
+func (m *Model) Save(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return errors.Wrapf(err, "could not create %s", path)
+	}
+	if filepath.Ext(path) == ".json" {
+		err = json.NewEncoder(f).Encode(m)
+	} else {
+		err = m.WriteBinary(f)
+	}
+	if err != nil {
+		f.Close()
+		return errors.Wrapf(err, "could not write %s", path)
+	}
+	return errors.Wrapf(f.Close(), "could not close %s", path)
+}
+
+// LoadModel reads the model in the file at path, as written by Save.
+func LoadModel(path string) (*Model, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not read %s", path)
+	}
+	defer f.Close()
+
+	m := new(Model)
+	if filepath.Ext(path) == ".json" {
+		err = json.NewDecoder(f).Decode(m)
+	} else {
+		m, err = ReadModel(bufio.NewReader(f))
+	}
+	return m, errors.Wrapf(err, "could not parse %s", path)
+}
@@ -0,0 +1,131 @@
+package linreg
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"path/filepath"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+
+	"github.com/campoy/goml/util"
+)
+
+func TestModel(t *testing.T) {
+	// y = 1 + 2*a - b, with a constant column that must be ignored.
+	X := mat.NewDense(5, 3, []float64{
+		0, 1, 7,
+		1, 0, 7,
+		2, 3, 7,
+		10, 4, 7,
+		4, 20, 7,
+	})
+	y := mat.NewDense(5, 1, []float64{0, 3, 2, 17, -11})
+
+	var m Model
+	if err := m.Fit(X, y); err != nil {
+		t.Fatalf("could not fit: %v", err)
+	}
+	raw := mat.NewDense(2, 3, []float64{3, 3, 7, -1, 5, 7})
+	checkPredictions(t, &m, raw, []float64{4, -6})
+	// Lambda is only used by Fit, but it is kept as part of the model.
+	m.Lambda = 0.5
+
+	if _, err := m.Predict(mat.NewDense(1, 2, nil)); err == nil {
+		t.Errorf("expected error predicting with the wrong number of features")
+	}
+	if _, err := new(Model).Predict(raw); err == nil {
+		t.Errorf("expected error predicting with a model that was not fitted")
+	}
+	if err := new(Model).Fit(X.Slice(0, 0, 0, 3), y.Slice(0, 0, 0, 1)); err == nil {
+		t.Errorf("expected error fitting without examples")
+	}
+	if err := new(Model).Fit(X, mat.NewDense(5, 1, nil).Slice(0, 5, 0, 0)); err == nil {
+		t.Errorf("expected error fitting without targets")
+	}
+
+	t.Run("json", func(t *testing.T) {
+		data, err := json.Marshal(&m)
+		if err != nil {
+			t.Fatalf("could not encode: %v", err)
+		}
+		var got Model
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatalf("could not decode: %v", err)
+		}
+		checkPredictions(t, &got, raw, []float64{4, -6})
+		if got.Lambda != m.Lambda {
+			t.Errorf("expected lambda %v; got %v", m.Lambda, got.Lambda)
+		}
+	})
+
+	t.Run("binary", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := m.WriteBinary(&buf); err != nil {
+			t.Fatalf("could not write: %v", err)
+		}
+		got, err := ReadModel(&buf)
+		if err != nil {
+			t.Fatalf("could not read: %v", err)
+		}
+		checkPredictions(t, got, raw, []float64{4, -6})
+		if got.Lambda != m.Lambda {
+			t.Errorf("expected lambda %v; got %v", m.Lambda, got.Lambda)
+		}
+	})
+
+	for _, name := range []string{"model.json", "model.bin"} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), name)
+			if err := m.Save(path); err != nil {
+				t.Fatalf("could not save: %v", err)
+			}
+			got, err := LoadModel(path)
+			if err != nil {
+				t.Fatalf("could not load: %v", err)
+			}
+			checkPredictions(t, got, raw, []float64{4, -6})
+		})
+	}
+}
+
+func TestModelBinaryEdgeCases(t *testing.T) {
+	// A model without features only predicts its intercept.
+	m := &Model{Means: []float64{}, StdDevs: []float64{}, Theta: mat.NewDense(1, 1, []float64{5})}
+	var buf bytes.Buffer
+	if err := m.WriteBinary(&buf); err != nil {
+		t.Fatalf("could not write: %v", err)
+	}
+	got, err := ReadModel(&buf)
+	if err != nil {
+		t.Fatalf("could not read: %v", err)
+	}
+	if len(got.Means) != 0 || got.Theta.At(0, 0) != 5 {
+		t.Errorf("expected a model without features predicting 5; got %+v", got)
+	}
+
+	// A theta without columns is rejected instead of panicking.
+	buf.Reset()
+	for _, v := range []util.Matrix{vector{1}, vector{1}, mat.NewDense(2, 1, nil).Slice(0, 2, 0, 0), vector{0}} {
+		if err := util.WriteBinary(&buf, v, util.Float64); err != nil {
+			t.Fatalf("could not write: %v", err)
+		}
+	}
+	if _, err := ReadModel(&buf); err == nil {
+		t.Errorf("expected error reading a theta without columns")
+	}
+}
+
+func checkPredictions(t *testing.T, m *Model, X *mat.Dense, want []float64) {
+	t.Helper()
+	pred, err := m.Predict(X)
+	if err != nil {
+		t.Fatalf("could not predict: %v", err)
+	}
+	for i, w := range want {
+		if got := pred.At(i, 0); math.Abs(got-w) > 1e-9 {
+			t.Errorf("expected prediction %v for row %d; got %v", w, i, got)
+		}
+	}
+}
@@ -1,29 +1,31 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"image/color"
 	"log"
 	"math/rand"
-	"os"
 	"time"
 
-	"github.com/campoy/goml/iplot/xyer"
+	"github.com/campoy/goml/iplot"
 	"github.com/campoy/goml/linreg"
 	"github.com/campoy/goml/util"
-	"github.com/campoy/tools/imgcat"
 	"gonum.org/v1/gonum/mat"
 	"gonum.org/v1/plot"
 	"gonum.org/v1/plot/plotter"
 )
 
 func main() {
+	plots := flag.String("plots", "", "directory where plots are saved instead of shown")
+	modelPath := flag.String("model", "", "file where the fitted model is saved, as JSON if it ends in .json")
+	flag.Parse()
+
 	rand.Seed(time.Now().Unix())
 
-	enc, err := imgcat.NewEncoder(os.Stdout,
-		imgcat.Width(imgcat.Cells(100)), imgcat.Inline(true))
+	sink, err := util.NewSink(*plots)
 	if err != nil {
-		fmt.Fprintf(os.Stdout, "images will not be shown: %v", err)
+		log.Fatal(err)
 	}
 
 	data, err := util.ParseMatrix("data.txt")
@@ -31,7 +33,7 @@ func main() {
 		log.Fatal(err)
 	}
 
-	X, y, theta := linreg.InitParameters(data)
+	X, y, theta := linreg.InitParameters(util.Gonum(data))
 	fmt.Println("First 10 examples from the dataset")
 	for i := 0; i < 10; i++ {
 		fmt.Printf("x = %v, y = %v\n", X.RawRowView(i)[1:], y.At(i, 0))
@@ -44,16 +46,49 @@ func main() {
 	}
 
 	fmt.Println("Running gradient descent")
-	theta, _, costs := linreg.GradientDescent(X, y, theta, 0.01, 400)
+	hist := &linreg.History{Every: 10}
+	theta, _, costs := linreg.GradientDescentWithHistory(X, y, theta, 0.01, 400, hist)
 
 	{ // plot a line with the costs
 		p, _ := plot.New()
-		l, _ := plotter.NewLine(xyer.FromSlice(costs))
+		xys := make(plotter.XYs, len(costs))
+		for i, step := range hist.Steps() {
+			xys[i].X, xys[i].Y = float64(step), costs[i]
+		}
+		l, _ := plotter.NewLine(xys)
 		p.Add(l)
 		l.Color = color.RGBA{R: 255, A: 255}
 		p.Title.Text = "Cost over time"
 		p.X.Label.Text = "number of iterations"
-		util.PrintPlot(enc, p)
+		if err := sink.WritePlot(p, 400, 400); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	{ // plot the value of every coefficient over time
+		p, err := iplot.Trajectories(hist.Steps(), hist.Thetas(), []string{"intercept", "size", "bedrooms"})
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := sink.WritePlot(p, 400, 400); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	{ // plot how the coefficients shrink with ridge regularization
+		lambdas := []float64{0.01, 0.1, 1, 10, 100, 1000}
+		path, err := linreg.RidgePath(X, y, lambdas)
+		if err != nil {
+			log.Fatal(err)
+		}
+		names := []string{"size", "bedrooms"}
+		p, err := iplot.RegularizationPath(lambdas, path.Slice(0, len(lambdas), 1, 3), names)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := sink.WritePlot(p, 400, 400); err != nil {
+			log.Fatal(err)
+		}
 	}
 
 	house := mat.NewDense(1, 3, []float64{1, 1650, 3})
@@ -66,4 +101,47 @@ func main() {
 	pred.Mul(house, theta)
 	fmt.Println("Predicted price of a 1650 sq-ft, 3 br house (using gradient descent):")
 	fmt.Printf("\t%.2f\n", pred.At(0, 0))
+
+	fmt.Println("Solving with normal equations")
+	X, y, _ = linreg.InitParameters(util.Gonum(data))
+	theta, err = linreg.NormalEquation(X, y)
+	if err != nil {
+		log.Fatal(err)
+	}
+	util.PrintMatrix("theta computed from the normal equations", theta)
+
+	pred.Mul(mat.NewDense(1, 3, []float64{1, 1650, 3}), theta)
+	fmt.Println("Predicted price of a 1650 sq-ft, 3 br house (using normal equations):")
+	fmt.Printf("\t%.2f\n", pred.At(0, 0))
+
+	inf, err := linreg.Infer(X, y, []string{"size", "bedrooms"})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(inf)
+	_, lo, hi, err := inf.PredictionIntervals(mat.NewDense(1, 3, []float64{1, 1650, 3}), 0.95)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("95%% prediction interval: [%.2f, %.2f]\n", lo[0], hi[0])
+
+	// A Model keeps the normalization and the intercept,
+	// so it can be used directly on raw features.
+	var model linreg.Model
+	r, c := data.Dims()
+	if err := model.Fit(util.Gonum(data.SliceCols(0, c-1)), util.Gonum(data.SliceCols(c-1, c))); err != nil {
+		log.Fatal(err)
+	}
+	if *modelPath != "" {
+		if err := model.Save(*modelPath); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("Model fitted on %d examples saved to %s\n", r, *modelPath)
+	}
+	pred, err = model.Predict(mat.NewDense(1, 2, []float64{1650, 3}))
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("Predicted price of a 1650 sq-ft, 3 br house (using a model):")
+	fmt.Printf("\t%.2f\n", pred.At(0, 0))
 }
@@ -0,0 +1,32 @@
+package linreg
+
+import (
+	"github.com/pkg/errors"
+	"gonum.org/v1/gonum/mat"
+)
+
+// rank returns the numerical rank of the m by n matrix factorized by svd: the
+// number of its singular values larger than max(m, n) times the machine
+// epsilon times the largest one, the default tolerance of LAPACK and NumPy.
+// Smaller singular values are only rounding errors of the factorization.
+func rank(svd *mat.SVD, m, n int) int {
+	return svd.Rank(float64(max(m, n)) * 0x1p-52)
+}
+
+// NormalEquation computes in closed form the theta that minimizes ComputeCost
+// for the given X and y, with the same shape as the one InitParameters returns.
+//
+// It solves the least squares problem through the singular value decomposition
+// of X, which is numerically more stable than inverting X'X. When X is rank
+// deficient, for instance because a feature is a copy of another one, it
+// returns the solution with minimum norm given by the pseudo-inverse.
+func NormalEquation(X, y *mat.Dense) (*mat.Dense, error) {
+	m, n := X.Dims()
+	var svd mat.SVD
+	if ok := svd.Factorize(X, mat.SVDThin); !ok {
+		return nil, errors.New("could not factorize X")
+	}
+	theta := new(mat.Dense)
+	svd.SolveTo(theta, y, rank(&svd, m, n))
+	return theta, nil
+}
@@ -0,0 +1,80 @@
+package linreg
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestNormalEquation(t *testing.T) {
+	tc := []struct {
+		name string
+		data []float64
+		cols int
+		want []float64
+	}{
+		{
+			name: "exact line",
+			data: []float64{0, 1, 1, 3, 2, 5, 3, 7},
+			cols: 2,
+			want: []float64{1, 2},
+		},
+		{
+			name: "two features",
+			data: []float64{1, 0, 4, 0, 1, 2, 1, 1, 5, 2, 3, 10},
+			cols: 3,
+			want: []float64{1, 3, 1},
+		},
+		{
+			// The second feature is a copy of the first one, so the minimum
+			// norm solution splits the coefficient 2 evenly between them.
+			name: "rank deficient",
+			data: []float64{0, 0, 1, 1, 1, 3, 2, 2, 5, 3, 3, 7},
+			cols: 3,
+			want: []float64{1, 1, 1},
+		},
+		{
+			// The third feature is 0.1 times the first one plus 0.3 times
+			// the second one, up to rounding, and y is 1 + 2*x1 + 3*x2.
+			// Moving along the null space (0, 0.1, 0.3, -1) gives the
+			// minimum norm solution.
+			name: "rank deficient by rounding",
+			data: collinear(
+				[]float64{7.8, 5.7, 2.2, 8.3, 0.1, 8.8},
+				[]float64{9.1, 8.3, 7.9, 1.8, 0.1, 3.2},
+			),
+			cols: 4,
+			want: []float64{1, 1.9, 2.7, 1},
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			data := mat.NewDense(len(tt.data)/tt.cols, tt.cols, tt.data)
+			X, y, _ := InitParameters(data)
+			theta, err := NormalEquation(X, y)
+			if err != nil {
+				t.Fatalf("could not solve: %v", err)
+			}
+			if r, c := theta.Dims(); r != tt.cols || c != 1 {
+				t.Fatalf("expected theta to be %dx1; got %dx%d", tt.cols, r, c)
+			}
+			for i, want := range tt.want {
+				if got := theta.At(i, 0); math.Abs(got-want) > 1e-9 {
+					t.Errorf("expected theta[%d] to be %v; got %v", i, want, got)
+				}
+			}
+		})
+	}
+}
+
+// collinear returns the rows x1, x2, 0.1*x1 + 0.3*x2 and 1 + 2*x1 + 3*x2
+// for the given values of x1 and x2.
+func collinear(x1, x2 []float64) []float64 {
+	var data []float64
+	for i := range x1 {
+		data = append(data, x1[i], x2[i], 0.1*x1[i]+0.3*x2[i], 1+2*x1[i]+3*x2[i])
+	}
+	return data
+}
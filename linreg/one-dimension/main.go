@@ -1,31 +1,36 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"image/color"
 	"log"
 	"math/rand"
-	"os"
 	"time"
 
-	"github.com/campoy/tools/imgcat"
-	"github.com/gonum/plot"
-	"github.com/gonum/plot/palette"
-	"github.com/gonum/plot/plotter"
-	"github.com/gonum/plot/vg/draw"
 	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg/draw"
 
 	"github.com/campoy/goml/iplot"
 	"github.com/campoy/goml/iplot/xyer"
 	"github.com/campoy/goml/linreg"
+	"github.com/campoy/goml/metrics"
 	"github.com/campoy/goml/util"
 )
 
 func main() {
+	plots := flag.String("plots", "", "directory where plots are saved instead of shown")
+	batch := flag.Int("batch", 0, "examples per gradient step, 0 to use all of them")
+	epochs := flag.Int("epochs", 200, "passes over the dataset when batch is not 0")
+	timeout := flag.Duration("timeout", 10*time.Second, "maximum time spent in gradient descent")
+	flag.Parse()
+
 	rand.Seed(time.Now().Unix())
 
-	enc, err := imgcat.NewEncoder(os.Stdout,
-		imgcat.Width(imgcat.Cells(100)), imgcat.Inline(true))
+	sink, err := util.NewSink(*plots)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -37,11 +42,11 @@ func main() {
 
 	m, _ := data.Dims()
 	X := mat.NewDense(m, 2, nil)
-	X.SetCol(1, mat.Col(nil, 0, data))
+	X.SetCol(1, mat.Col(nil, 0, util.Gonum(data)))
 	for i := 0; i < m; i++ {
 		X.Set(i, 0, 1)
 	}
-	y := mat.NewDense(m, 1, mat.Col(nil, 1, data))
+	y := mat.NewDense(m, 1, mat.Col(nil, 1, util.Gonum(data)))
 
 	{ // plot scatter of the points
 		fmt.Println("Dataset plot")
@@ -53,7 +58,9 @@ func main() {
 		p.Title.Text = "Population and Profit"
 		p.X.Label.Text = "Population of City in 10,000s"
 		p.Y.Label.Text = "Profit in $10,000s"
-		util.PrintPlot(enc, p)
+		if err := sink.WritePlot(p, 400, 400); err != nil {
+			log.Fatal(err)
+		}
 	}
 
 	theta := mat.NewDense(2, 1, make([]float64, 2))
@@ -62,8 +69,27 @@ func main() {
 	fmt.Printf("initial cost is %f\n", cost)
 
 	alpha := 0.01
-	theta, thetas, costs := linreg.GradientDescent(X, y, theta, alpha, 1500)
-	fmt.Printf("took %d steps\n", len(thetas))
+	var thetas [][]float64
+	var costs []float64
+	if *batch > 0 {
+		theta, thetas, costs = linreg.MiniBatchGradientDescent(X, y, theta, alpha,
+			linreg.BatchOptions{BatchSize: *batch, Epochs: *epochs, Seed: rand.Int63()}, nil)
+	} else {
+		ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+		defer cancel()
+		var reason linreg.StopReason
+		theta, thetas, costs, reason, err = linreg.GradientDescentContext(ctx, X, y, theta, alpha, 0, 1500,
+			linreg.Convergence{CostTol: 1e-9, MaxIncrease: 100}, nil)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("gradient descent stopped: %v\n", reason)
+	}
+	unit := "iterations"
+	if *batch > 0 {
+		unit = "epochs"
+	}
+	fmt.Printf("took %d %s\n", len(thetas), unit)
 	util.PrintMatrix("theta", theta)
 	fmt.Printf("current cost is %f\n", linreg.ComputeCost(X, y, theta))
 
@@ -73,27 +99,24 @@ func main() {
 		p.Add(l)
 		l.Color = color.RGBA{R: 255, A: 255}
 		p.Title.Text = "Cost over time"
-		p.X.Label.Text = "number of iterations"
-		util.PrintPlot(enc, p)
+		p.X.Label.Text = "number of " + unit
+		if err := sink.WritePlot(p, 400, 400); err != nil {
+			log.Fatal(err)
+		}
 	}
 
 	{ // plot optimization space
 		fmt.Println("optimization space and traject")
-		p, _ := plot.New()
-		p.Add(plotter.NewContour(
-			iplot.GridXYZ(iplot.NewRange(-8, 5, 100), iplot.NewRange(0, 3, 100),
-				func(a, b float64) float64 {
-					return linreg.ComputeCost(X, y, mat.NewDense(2, 1, []float64{a, b}))
-				}),
-			nil, palette.Heat(16, 1)))
-		s, _ := plotter.NewScatter(xyer.FromSliceOfSlices(thetas))
-		s.Radius = 1
-		s.Shape = draw.CrossGlyph{}
-		p.Add(s)
-		p.Title.Text = "Optimization path"
-		p.X.Label.Text = "theta0"
-		p.Y.Label.Text = "theta1"
-		util.PrintPlot(enc, p)
+		p, err := iplot.ContourPath(iplot.NewRange(-8, 5, 100), iplot.NewRange(0, 3, 100),
+			func(a, b float64) float64 {
+				return linreg.ComputeCost(X, y, mat.NewDense(2, 1, []float64{a, b}))
+			}, thetas, 0, 1)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := sink.WritePlot(p, 400, 400); err != nil {
+			log.Fatal(err)
+		}
 	}
 
 	pred := new(mat.Dense)
@@ -116,6 +139,19 @@ func main() {
 		p.Add(plotter.NewFunction(func(x float64) float64 {
 			return theta.At(0, 0) + theta.At(1, 0)*x
 		}))
-		util.PrintPlot(enc, p)
+		if err := sink.WritePlot(p, 400, 400); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	fitted := new(mat.Dense)
+	fitted.Mul(X, theta)
+	report, err := metrics.Regression(y, fitted, 1)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("scores of the fit:\n%v", report)
+	if err := metrics.WritePlots(sink, y, fitted); err != nil {
+		log.Fatal(err)
 	}
 }
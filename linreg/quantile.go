@@ -0,0 +1,159 @@
+package linreg
+
+import (
+	"math"
+
+	"github.com/pkg/errors"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/optimize/convex/lp"
+)
+
+// PinballLoss computes the mean pinball loss of using theta to predict the
+// quantile tau of y given X, with tau between 0 and 1. Residuals above the
+// prediction cost tau times their size, and the ones below cost 1-tau times,
+// so the loss is minimized by the conditional quantile tau of y.
+func PinballLoss(X, y, theta *mat.Dense, tau float64) float64 {
+	m, _ := X.Dims()
+	sum := 0.0
+	for _, r := range residuals(X, y, theta) {
+		if r >= 0 {
+			sum += tau * r
+		} else {
+			sum -= (1 - tau) * r
+		}
+	}
+	return sum / float64(m)
+}
+
+// maxSimplexExamples is the largest number of examples for which
+// QuantileRegression solves the exact linear program. The dense simplex
+// method takes most of a second for 200 examples, and its time grows faster
+// than the cube of the number of examples.
+const maxSimplexExamples = 250
+
+// QuantileRegression fits a linear regression for each of the quantiles taus
+// of y given X, such as 0.1, 0.5 and 0.9 for the 10th, 50th and 90th
+// percentiles, with X and y as returned by InitParameters. It returns a matrix
+// with a column of coefficients per quantile, so the predictions for all the
+// quantiles are the columns of X times it.
+//
+// With up to 250 examples, each quantile is fitted by minimizing PinballLoss
+// exactly, as the linear program with theta = p - q and residuals
+// y - X*theta = u - v:
+//
+//	minimize tau*sum(u) + (1-tau)*sum(v)
+//	subject to X*(p-q) + u - v = y, and p, q, u, v >= 0
+//
+// solved with the dense simplex method, whose cost grows too fast for more
+// examples. Larger datasets are fitted with QuantileIRLS instead.
+func QuantileRegression(X, y *mat.Dense, taus []float64) (*mat.Dense, error) {
+	if m, _ := X.Dims(); m > maxSimplexExamples {
+		return QuantileIRLS(X, y, taus, 100, 1e-9)
+	}
+	return quantileSimplex(X, y, taus)
+}
+
+// checkQuantiles returns an error unless y has a single column and all the
+// quantiles are between 0 and 1.
+func checkQuantiles(y *mat.Dense, taus []float64) error {
+	if _, k := y.Dims(); k != 1 {
+		return errors.Errorf("expected a single column of targets, got %d", k)
+	}
+	for _, tau := range taus {
+		if tau <= 0 || tau >= 1 {
+			return errors.Errorf("quantiles must be between 0 and 1, got %v", tau)
+		}
+	}
+	return nil
+}
+
+// quantileSimplex fits QuantileRegression by solving its linear program.
+func quantileSimplex(X, y *mat.Dense, taus []float64) (*mat.Dense, error) {
+	if err := checkQuantiles(y, taus); err != nil {
+		return nil, err
+	}
+	m, n := X.Dims()
+	A := mat.NewDense(m, 2*n+2*m, nil)
+	A.Slice(0, m, 0, n).(*mat.Dense).Copy(X)
+	A.Slice(0, m, n, 2*n).(*mat.Dense).Scale(-1, X)
+	b := mat.Col(nil, 0, y)
+	// Starting with the residuals as the only non-zero variables gives
+	// a feasible solution, taking u or v depending on the sign of y.
+	basic := make([]int, m)
+	for i, v := range b {
+		A.Set(i, 2*n+i, 1)
+		A.Set(i, 2*n+m+i, -1)
+		basic[i] = 2*n + i
+		if v < 0 {
+			basic[i] = 2*n + m + i
+		}
+	}
+
+	thetas := mat.NewDense(n, len(taus), nil)
+	c := make([]float64, 2*n+2*m)
+	for k, tau := range taus {
+		for i := 0; i < m; i++ {
+			c[2*n+i], c[2*n+m+i] = tau, 1-tau
+		}
+		_, x, err := lp.Simplex(c, A, b, 1e-10, basic)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not fit quantile %v", tau)
+		}
+		for j := 0; j < n; j++ {
+			thetas.Set(j, k, x[j]-x[n+j])
+		}
+	}
+	return thetas, nil
+}
+
+// QuantileIRLS works like QuantileRegression, but fits each quantile with
+// iteratively reweighted least squares, which scales to large datasets at
+// the cost of an approximate minimum of PinballLoss.
+//
+// Starting from the least squares fit, it solves WeightedEquation with the
+// weight of each example being tau or 1-tau, depending on the sign of its
+// residual, divided by the absolute value of the residual, for at most iters
+// iterations or until no coefficient changes by more than tol.
+func QuantileIRLS(X, y *mat.Dense, taus []float64, iters int, tol float64) (*mat.Dense, error) {
+	if err := checkQuantiles(y, taus); err != nil {
+		return nil, err
+	}
+	m, n := X.Dims()
+	ols, err := NormalEquation(X, y)
+	if err != nil {
+		return nil, err
+	}
+	// Residuals smaller than eps are weighted as if they were eps, so the
+	// examples fitted exactly do not get an infinite weight.
+	eps := 1e-8
+	for _, r := range residuals(X, y, ols) {
+		eps = math.Max(eps, 1e-8*math.Abs(r))
+	}
+
+	thetas := mat.NewDense(n, len(taus), nil)
+	w := make([]float64, m)
+	for k, tau := range taus {
+		theta := ols
+		for it := 0; it < iters; it++ {
+			for i, r := range residuals(X, y, theta) {
+				w[i] = tau
+				if r < 0 {
+					w[i] = 1 - tau
+				}
+				w[i] /= math.Max(math.Abs(r), eps)
+			}
+			next, err := WeightedEquation(X, y, w)
+			if err != nil {
+				return nil, errors.Wrapf(err, "could not fit quantile %v at iteration %d", tau, it)
+			}
+			diff := new(mat.Dense)
+			diff.Sub(next, theta)
+			theta = next
+			if mat.Norm(diff, math.Inf(1)) <= tol {
+				break
+			}
+		}
+		thetas.SetCol(k, mat.Col(nil, 0, theta))
+	}
+	return thetas, nil
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+
+	"gonum.org/v1/gonum/mat"
+
+	"github.com/campoy/goml/iplot"
+	"github.com/campoy/goml/linreg"
+	"github.com/campoy/goml/util"
+)
+
+func main() {
+	plots := flag.String("plots", "", "directory where plots are saved instead of shown")
+	seed := flag.Int64("seed", 1, "seed used to generate the data")
+	flag.Parse()
+
+	sink, err := util.NewSink(*plots)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// noise growing with x, so the quantiles spread apart as x grows
+	rng := rand.New(rand.NewSource(*seed))
+	data := mat.NewDense(200, 2, nil)
+	for i := 0; i < 200; i++ {
+		x := 10 * rng.Float64()
+		data.SetRow(i, []float64{x, 1 + 2*x + (0.1+0.5*x)*rng.NormFloat64()})
+	}
+	X, y, _ := linreg.InitParameters(data)
+
+	taus := []float64{0.1, 0.5, 0.9}
+	thetas, err := linreg.QuantileRegression(X, y, taus)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for k, tau := range taus {
+		theta := mat.NewDense(2, 1, mat.Col(nil, k, thetas))
+		fmt.Printf("p%g: y = %.3f + %.3f x (pinball loss %.4f)\n",
+			100*tau, theta.At(0, 0), theta.At(1, 0), linreg.PinballLoss(X, y, theta, tau))
+	}
+
+	p, err := iplot.QuantileBands(X, y, taus, thetas)
+	if err != nil {
+		log.Fatal(err)
+	}
+	p.Legend.Top = true
+	p.X.Label.Text = "x"
+	p.Y.Label.Text = "y"
+	if err := sink.WritePlot(p, 400, 400); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,119 @@
+package linreg
+
+import (
+	"math"
+	"testing"
+
+	"github.com/campoy/goml/datagen"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestPinballLoss(t *testing.T) {
+	X := mat.NewDense(2, 1, []float64{1, 1})
+	y := mat.NewDense(2, 1, []float64{0, 4})
+	theta := mat.NewDense(1, 1, []float64{1})
+
+	// Residuals are -1 and 3.
+	if got, want := PinballLoss(X, y, theta, 0.9), (0.1*1+0.9*3)/2; math.Abs(got-want) > 1e-12 {
+		t.Errorf("expected loss %v; got %v", want, got)
+	}
+	// For the median it is half the mean absolute error, (1+3)/2/2.
+	if got := PinballLoss(X, y, theta, 0.5); math.Abs(got-1) > 1e-12 {
+		t.Errorf("expected median loss 1; got %v", got)
+	}
+}
+
+func TestQuantileRegression(t *testing.T) {
+	X, y, _ := InitParameters(datagen.Linear(200, []float64{1, 2}, 1, 0, 1))
+	m, _ := X.Dims()
+	taus := []float64{0.1, 0.5, 0.9}
+
+	thetas, err := QuantileRegression(X, y, taus)
+	if err != nil {
+		t.Fatalf("could not fit: %v", err)
+	}
+	if r, c := thetas.Dims(); r != 2 || c != 3 {
+		t.Fatalf("expected 2x3 coefficients; got %dx%d", r, c)
+	}
+
+	for k, tau := range taus {
+		theta := mat.NewDense(2, 1, mat.Col(nil, k, thetas))
+
+		// The fraction of examples under the fitted quantile is tau,
+		// up to the number of coefficients.
+		below := 0
+		for _, r := range residuals(X, y, theta) {
+			if r < -1e-9 {
+				below++
+			}
+		}
+		if got := float64(below) / float64(m); math.Abs(got-tau) > 2.0/float64(m)+1e-9 {
+			t.Errorf("quantile %v: expected a fraction %v of examples below; got %v", tau, tau, got)
+		}
+
+		// No nearby theta has a smaller loss.
+		loss := PinballLoss(X, y, theta, tau)
+		for _, d := range [][]float64{{0.01, 0}, {-0.01, 0}, {0, 0.01}, {0, -0.01}} {
+			other := mat.NewDense(2, 1, []float64{theta.At(0, 0) + d[0], theta.At(1, 0) + d[1]})
+			if l := PinballLoss(X, y, other, tau); l < loss-1e-12 {
+				t.Errorf("quantile %v: expected loss %v to be minimal; got %v for %v", tau, loss, l, d)
+			}
+		}
+	}
+
+	// With normal noise of deviation 1, the 10th and 90th percentiles
+	// are 1.28 under and over the line.
+	if got := thetas.At(0, 2) - thetas.At(0, 0); math.Abs(got-2*1.2816) > 0.5 {
+		t.Errorf("expected intercepts of p10 and p90 to be about 2.56 apart; got %v", got)
+	}
+	if got := thetas.At(1, 1); math.Abs(got-2) > 0.2 {
+		t.Errorf("expected slope of the median to be about 2; got %v", got)
+	}
+
+	if _, err := QuantileRegression(X, y, []float64{1}); err == nil {
+		t.Errorf("expected error for quantile 1")
+	}
+}
+
+func TestQuantileIRLS(t *testing.T) {
+	// IRLS gets close to the exact minimum of the simplex method.
+	X, y, _ := InitParameters(datagen.Linear(200, []float64{1, 2}, 1, 0.1, 3))
+	taus := []float64{0.1, 0.5, 0.9}
+	exact, err := quantileSimplex(X, y, taus)
+	if err != nil {
+		t.Fatalf("could not solve: %v", err)
+	}
+	approx, err := QuantileIRLS(X, y, taus, 100, 1e-9)
+	if err != nil {
+		t.Fatalf("could not fit: %v", err)
+	}
+	for k, tau := range taus {
+		want := PinballLoss(X, y, mat.NewDense(2, 1, mat.Col(nil, k, exact)), tau)
+		got := PinballLoss(X, y, mat.NewDense(2, 1, mat.Col(nil, k, approx)), tau)
+		if got < want-1e-9 || got > want*(1+1e-4) {
+			t.Errorf("quantile %v: expected loss close to %v; got %v", tau, want, got)
+		}
+	}
+
+	// Larger datasets are fitted with IRLS, which is fast.
+	X, y, _ = InitParameters(datagen.Linear(5000, []float64{1, 2}, 1, 0, 4))
+	m, _ := X.Dims()
+	thetas, err := QuantileRegression(X, y, taus)
+	if err != nil {
+		t.Fatalf("could not fit: %v", err)
+	}
+	for k, tau := range taus {
+		below := 0
+		for _, r := range residuals(X, y, mat.NewDense(2, 1, mat.Col(nil, k, thetas))) {
+			if r < 0 {
+				below++
+			}
+		}
+		if got := float64(below) / float64(m); math.Abs(got-tau) > 0.01 {
+			t.Errorf("quantile %v: expected a fraction %v of examples below; got %v", tau, tau, got)
+		}
+	}
+	if _, err := QuantileRegression(X, y, []float64{0}); err == nil {
+		t.Errorf("expected error for quantile 0")
+	}
+}
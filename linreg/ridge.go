@@ -0,0 +1,83 @@
+package linreg
+
+import (
+	"context"
+	"math"
+
+	"github.com/pkg/errors"
+	"gonum.org/v1/gonum/mat"
+
+	"github.com/campoy/goml/schedule"
+)
+
+// ComputeCostReg computes the cost of using theta as the parameter for linear
+// regression to fit the data points in X and y, adding an L2 penalty of
+// lambda/2m times the sum of the squared coefficients. The first coefficient
+// multiplies the column of ones added by InitParameters, so as the intercept
+// it is not penalized.
+func ComputeCostReg(X, y, theta *mat.Dense, lambda float64) float64 {
+	m, _ := X.Dims()
+	cost := ComputeCost(X, y, theta)
+	if lambda == 0 {
+		return cost
+	}
+	r, c := theta.Dims()
+	sum := 0.0
+	for i := 1; i < r; i++ {
+		for j := 0; j < c; j++ {
+			sum += theta.At(i, j) * theta.At(i, j)
+		}
+	}
+	return cost + lambda*sum/float64(2*m)
+}
+
+// GradientDescentReg works like GradientDescent, but minimizes ComputeCostReg
+// with the given lambda instead of ComputeCost.
+func GradientDescentReg(X, y, theta *mat.Dense, alpha, lambda float64, iters int, hist *History) (*mat.Dense, [][]float64, []float64) {
+	theta, thetas, costs, _, _ := GradientDescentSchedule(context.Background(), X, y, theta,
+		schedule.Constant(alpha), lambda, iters, Convergence{}, hist)
+	return theta, thetas, costs
+}
+
+// RidgeEquation computes in closed form the theta that minimizes
+// ComputeCostReg for the given X, y and lambda.
+//
+// It solves (X'X + lambda*L) theta = X'y, where L is the identity matrix
+// without its first element, as the least squares problem obtained by
+// stacking sqrt(lambda)*L under X and zeros under y, using NormalEquation.
+func RidgeEquation(X, y *mat.Dense, lambda float64) (*mat.Dense, error) {
+	if lambda < 0 {
+		return nil, errors.Errorf("lambda must not be negative, got %v", lambda)
+	}
+	if lambda == 0 {
+		return NormalEquation(X, y)
+	}
+
+	m, n := X.Dims()
+	_, k := y.Dims()
+	A := mat.NewDense(m+n-1, n, nil)
+	A.Slice(0, m, 0, n).(*mat.Dense).Copy(X)
+	for j := 1; j < n; j++ {
+		A.Set(m+j-1, j, math.Sqrt(lambda))
+	}
+	b := mat.NewDense(m+n-1, k, nil)
+	b.Slice(0, m, 0, k).(*mat.Dense).Copy(y)
+	return NormalEquation(A, b)
+}
+
+// RidgePath fits RidgeEquation for each of the given lambdas, and returns
+// the coefficients obtained with each of them as the rows of a matrix.
+// Features should be normalized first, so the penalty is comparable
+// across coefficients.
+func RidgePath(X, y *mat.Dense, lambdas []float64) (*mat.Dense, error) {
+	_, n := X.Dims()
+	path := mat.NewDense(len(lambdas), n, nil)
+	for i, lambda := range lambdas {
+		theta, err := RidgeEquation(X, y, lambda)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not fit lambda %v", lambda)
+		}
+		path.SetRow(i, mat.Col(nil, 0, theta))
+	}
+	return path, nil
+}
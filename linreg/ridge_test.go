@@ -0,0 +1,62 @@
+package linreg
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestComputeCostReg(t *testing.T) {
+	X := mat.NewDense(2, 2, []float64{1, 1, 1, 2})
+	y := mat.NewDense(2, 1, []float64{1, 2})
+	theta := mat.NewDense(2, 1, []float64{3, 1})
+
+	// The squared errors add up to 18, and only the second
+	// coefficient is penalized: (18 + 10*1) / 4.
+	if got := ComputeCostReg(X, y, theta, 10); got != 7 {
+		t.Errorf("expected cost 7; got %v", got)
+	}
+	if got, want := ComputeCostReg(X, y, theta, 0), ComputeCost(X, y, theta); got != want {
+		t.Errorf("expected cost without penalty to be %v; got %v", want, got)
+	}
+}
+
+func TestRidge(t *testing.T) {
+	data := mat.NewDense(5, 3, []float64{
+		1, 2, 7,
+		2, 1, 6,
+		3, 4, 15,
+		4, 3, 14,
+		5, 5, 21,
+	})
+	X, y, theta := InitParameters(data)
+	NormalizeFeatures(X)
+
+	for _, lambda := range []float64{0, 1, 10} {
+		want, err := RidgeEquation(X, y, lambda)
+		if err != nil {
+			t.Fatalf("could not solve: %v", err)
+		}
+		got, _, _ := GradientDescentReg(X, y, mat.DenseCopyOf(theta), 0.1, lambda, 5000, nil)
+		for i := 0; i < 3; i++ {
+			if math.Abs(got.At(i, 0)-want.At(i, 0)) > 1e-6 {
+				t.Errorf("lambda %v: expected theta[%d] to be %v; got %v", lambda, i, want.At(i, 0), got.At(i, 0))
+			}
+		}
+	}
+
+	path, err := RidgePath(X, y, []float64{0.1, 1, 10, 100})
+	if err != nil {
+		t.Fatalf("could not compute path: %v", err)
+	}
+	for i := 1; i < 4; i++ {
+		if path.At(i, 0) != path.At(0, 0) && math.Abs(path.At(i, 0)-path.At(0, 0)) > 1e-9 {
+			t.Errorf("expected intercept not to change; got %v and %v", path.At(0, 0), path.At(i, 0))
+		}
+		prev := math.Hypot(path.At(i-1, 1), path.At(i-1, 2))
+		if cur := math.Hypot(path.At(i, 1), path.At(i, 2)); cur >= prev {
+			t.Errorf("expected coefficients to shrink with lambda; got norms %v then %v", prev, cur)
+		}
+	}
+}
@@ -0,0 +1,280 @@
+package linreg
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/pkg/errors"
+	"gonum.org/v1/gonum/mat"
+
+	"github.com/campoy/goml/schedule"
+)
+
+// ComputeCostWeighted computes the cost of using theta as the parameter for
+// linear regression to fit the data points in X and y, where the squared error
+// of the ith example is multiplied by w[i]. With all weights set to 1 it is
+// the same as ComputeCost. It fails unless there is a non-negative weight
+// for each example.
+func ComputeCostWeighted(X, y, theta *mat.Dense, w []float64) (float64, error) {
+	if err := checkWeights(X, w); err != nil {
+		return 0, err
+	}
+	return costWeighted(X, y, theta, w), nil
+}
+
+// costWeighted is ComputeCostWeighted for weights already checked.
+func costWeighted(X, y, theta *mat.Dense, w []float64) float64 {
+	m, _ := X.Dims()
+	h := new(mat.Dense)
+	h.Mul(X, theta)
+	h.Sub(h, y)
+	sum := 0.0
+	for i := 0; i < m; i++ {
+		for _, v := range h.RawRowView(i) {
+			sum += w[i] * v * v
+		}
+	}
+	return sum / float64(2*m)
+}
+
+// GradientDescentWeighted works like GradientDescent, but minimizes
+// ComputeCostWeighted with the given weights instead of ComputeCost.
+// It fails when the weights are not valid, and with a DivergenceError
+// when the cost becomes NaN or infinite.
+func GradientDescentWeighted(X, y, theta *mat.Dense, w []float64, alpha float64, iters int, hist *History) (*mat.Dense, [][]float64, []float64, error) {
+	if err := checkWeights(X, w); err != nil {
+		return nil, nil, nil, err
+	}
+	theta, thetas, costs, _, err := descend(context.Background(), theta, objective{
+		cost: func(theta *mat.Dense) float64 { return costWeighted(X, y, theta, w) },
+		grad: func(theta *mat.Dense) *mat.Dense { return gradientWeighted(X, y, theta, w) },
+	}, schedule.Constant(alpha), iters, Convergence{}, hist)
+	return theta, thetas, costs, err
+}
+
+// gradientWeighted returns the gradient of ComputeCostWeighted at theta.
+func gradientWeighted(X, y, theta *mat.Dense, w []float64) *mat.Dense {
+	m, _ := X.Dims()
+	r := new(mat.Dense)
+	r.Mul(X, theta)
+	r.Sub(r, y)
+	for i := 0; i < m; i++ {
+		row := r.RawRowView(i)
+		for j := range row {
+			row[j] *= w[i]
+		}
+	}
+	grad := new(mat.Dense)
+	grad.Mul(X.T(), r)
+	grad.Scale(1/float64(m), grad)
+	return grad
+}
+
+// checkWeights returns an error unless w has a non-negative weight for each
+// row of X.
+func checkWeights(X *mat.Dense, w []float64) error {
+	m, _ := X.Dims()
+	if len(w) != m {
+		return errors.Errorf("got %d weights for %d examples", len(w), m)
+	}
+	for i, v := range w {
+		if v < 0 {
+			return errors.Errorf("weight %d is negative: %v", i, v)
+		}
+	}
+	return nil
+}
+
+// WeightedEquation computes in closed form the theta that minimizes
+// ComputeCostWeighted, by solving with NormalEquation the problem where
+// each row of X and y is multiplied by the square root of its weight.
+func WeightedEquation(X, y *mat.Dense, w []float64) (*mat.Dense, error) {
+	if err := checkWeights(X, w); err != nil {
+		return nil, err
+	}
+	wx, wy := mat.DenseCopyOf(X), mat.DenseCopyOf(y)
+	for i, v := range w {
+		s := math.Sqrt(v)
+		for _, row := range [][]float64{wx.RawRowView(i), wy.RawRowView(i)} {
+			for j := range row {
+				row[j] *= s
+			}
+		}
+	}
+	return NormalEquation(wx, wy)
+}
+
+// residuals returns y - X*theta for a single column of targets.
+func residuals(X, y, theta *mat.Dense) []float64 {
+	r := new(mat.Dense)
+	r.Mul(X, theta)
+	r.Sub(y, r)
+	return mat.Col(nil, 0, r)
+}
+
+// mad returns the median absolute deviation of xs from zero, scaled to be an
+// estimate of the standard deviation of normally distributed values.
+func mad(xs []float64) float64 {
+	abs := make([]float64, len(xs))
+	for i, x := range xs {
+		abs[i] = math.Abs(x)
+	}
+	sort.Float64s(abs)
+	n := len(abs)
+	median := abs[n/2]
+	if n%2 == 0 {
+		median = (abs[n/2-1] + abs[n/2]) / 2
+	}
+	return median / 0.6745
+}
+
+// Huber fits a linear regression that minimizes the Huber loss of the
+// residuals: squared for residuals smaller than delta times their scale,
+// and linear for larger ones, so outliers have less influence on the fit.
+// A delta of 1.345 is as efficient as least squares for normal errors.
+//
+// It uses iteratively reweighted least squares, starting from the least
+// squares fit, for at most iters iterations or until no coefficient changes
+// by more than tol. The scale of the residuals is estimated on every iteration
+// from their median absolute deviation. It returns theta and the final weight
+// of every example, where the ones smaller than 1 indicate outliers.
+func Huber(X, y *mat.Dense, delta float64, iters int, tol float64) (*mat.Dense, []float64, error) {
+	m, _ := X.Dims()
+	if _, k := y.Dims(); k != 1 {
+		return nil, nil, errors.Errorf("expected a single column of targets, got %d", k)
+	}
+	theta, err := NormalEquation(X, y)
+	if err != nil {
+		return nil, nil, err
+	}
+	w := make([]float64, m)
+	for it := 0; it < iters; it++ {
+		if !huberWeights(residuals(X, y, theta), delta, w) {
+			break
+		}
+		next, err := WeightedEquation(X, y, w)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "could not fit iteration %d", it)
+		}
+		diff := new(mat.Dense)
+		diff.Sub(next, theta)
+		theta = next
+		if mat.Norm(diff, math.Inf(1)) <= tol {
+			break
+		}
+	}
+	huberWeights(residuals(X, y, theta), delta, w)
+	return theta, w, nil
+}
+
+// huberWeights sets the weights of the Huber loss for the given residuals
+// into w. It sets all of them to 1 and returns false when the scale of the
+// residuals is zero, since the fit is exact then.
+func huberWeights(res []float64, delta float64, w []float64) bool {
+	scale := mad(res)
+	for i, r := range res {
+		w[i] = 1
+		if a := math.Abs(r) / scale; scale > 0 && a > delta {
+			w[i] = delta / a
+		}
+	}
+	return scale > 0
+}
+
+// RANSACOptions configures RANSAC.
+type RANSACOptions struct {
+	// MinSamples is the number of examples used to fit each candidate.
+	// It defaults to the number of columns of X.
+	MinSamples int
+	// Threshold is the largest absolute residual of an inlier. It defaults
+	// to the median absolute deviation of y from its median.
+	Threshold float64
+	// Iters is the number of candidates tried, 100 by default.
+	Iters int
+	// Seed is used to choose the examples of each candidate.
+	Seed int64
+}
+
+// RANSAC fits a linear regression robust to outliers with the RANdom SAmple
+// Consensus algorithm. It fits NormalEquation on random subsets of the
+// examples, and keeps the candidate with the most inliers, breaking ties with
+// the lowest error on them. It returns theta fitted on all the inliers of the
+// best candidate, and the mask of which examples are inliers.
+func RANSAC(X, y *mat.Dense, opts RANSACOptions) (*mat.Dense, []bool, error) {
+	m, n := X.Dims()
+	if _, k := y.Dims(); k != 1 {
+		return nil, nil, errors.Errorf("expected a single column of targets, got %d", k)
+	}
+	if opts.MinSamples == 0 {
+		opts.MinSamples = n
+	}
+	if opts.MinSamples <= 0 {
+		return nil, nil, errors.Errorf("MinSamples must be positive, got %d", opts.MinSamples)
+	}
+	if opts.MinSamples > m {
+		return nil, nil, errors.Errorf("need %d examples, got %d", opts.MinSamples, m)
+	}
+	if opts.Iters == 0 {
+		opts.Iters = 100
+	}
+	if opts.Threshold == 0 {
+		ys := mat.Col(nil, 0, y)
+		sorted := append([]float64(nil), ys...)
+		sort.Float64s(sorted)
+		median := sorted[m/2]
+		for i := range ys {
+			ys[i] -= median
+		}
+		opts.Threshold = mad(ys) * 0.6745
+	}
+
+	rng := rand.New(rand.NewSource(opts.Seed))
+	var best []bool
+	bestCount, bestErr := 0, math.Inf(1)
+	for it := 0; it < opts.Iters; it++ {
+		sample := rng.Perm(m)[:opts.MinSamples]
+		theta, err := NormalEquation(rowsOf(X, sample), rowsOf(y, sample))
+		if err != nil {
+			continue
+		}
+		inliers := make([]bool, m)
+		count, sq := 0, 0.0
+		for i, r := range residuals(X, y, theta) {
+			if math.Abs(r) <= opts.Threshold {
+				inliers[i] = true
+				count++
+				sq += r * r
+			}
+		}
+		if count > bestCount || (count == bestCount && sq < bestErr) {
+			best, bestCount, bestErr = inliers, count, sq
+		}
+	}
+	if bestCount < opts.MinSamples {
+		return nil, nil, errors.Errorf("could not find a candidate with %d inliers", opts.MinSamples)
+	}
+
+	var idx []int
+	for i, in := range best {
+		if in {
+			idx = append(idx, i)
+		}
+	}
+	theta, err := NormalEquation(rowsOf(X, idx), rowsOf(y, idx))
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not fit inliers")
+	}
+	return theta, best, nil
+}
+
+// rowsOf returns a new matrix with the given rows of m.
+func rowsOf(m *mat.Dense, idx []int) *mat.Dense {
+	_, c := m.Dims()
+	out := mat.NewDense(len(idx), c, nil)
+	for i, row := range idx {
+		out.SetRow(i, m.RawRowView(row))
+	}
+	return out
+}
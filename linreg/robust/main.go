@@ -0,0 +1,130 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"image/color"
+	"log"
+	"math/rand"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg/draw"
+
+	"github.com/campoy/goml/datagen"
+	"github.com/campoy/goml/linreg"
+	"github.com/campoy/goml/util"
+)
+
+func main() {
+	plots := flag.String("plots", "", "directory where plots are saved instead of shown")
+	seed := flag.Int64("seed", 1, "seed used to generate the data")
+	flag.Parse()
+
+	sink, err := util.NewSink(*plots)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	{ // noise growing with x, fitted giving less weight to the noisiest examples
+		rng := rand.New(rand.NewSource(*seed))
+		data := mat.NewDense(100, 2, nil)
+		w := make([]float64, 100)
+		for i := range w {
+			x := 10 * rng.Float64()
+			std := 0.1 + 0.5*x
+			data.SetRow(i, []float64{x, 1 + 2*x + std*rng.NormFloat64()})
+			w[i] = 1 / (std * std)
+		}
+		X, y, _ := linreg.InitParameters(data)
+		plain := fit(linreg.NormalEquation(X, y))
+		weighted := fit(linreg.WeightedEquation(X, y, w))
+		report("weighted least squares", plain, weighted)
+		write(sink, compare("Weighted least squares", X, y, nil, plain, weighted))
+	}
+
+	// a tenth of the examples are outliers
+	X, y, _ := linreg.InitParameters(datagen.Linear(100, []float64{1, 2}, 0.5, 0.1, *seed))
+	plain := fit(linreg.NormalEquation(X, y))
+
+	{ // Huber loss through iteratively reweighted least squares
+		theta, _, err := linreg.Huber(X, y, 1.345, 50, 1e-6)
+		huber := fit(theta, err)
+		report("huber", plain, huber)
+		write(sink, compare("Huber", X, y, nil, plain, huber))
+	}
+
+	{ // RANSAC, showing the examples it considered outliers
+		theta, inliers, err := linreg.RANSAC(X, y, linreg.RANSACOptions{Seed: *seed})
+		ransac := fit(theta, err)
+		report("ransac", plain, ransac)
+		write(sink, compare("RANSAC", X, y, inliers, plain, ransac))
+	}
+}
+
+func fit(theta *mat.Dense, err error) *mat.Dense {
+	if err != nil {
+		log.Fatal(err)
+	}
+	return theta
+}
+
+func report(name string, plain, theta *mat.Dense) {
+	fmt.Printf("%s: y = %.3f + %.3f x (least squares: y = %.3f + %.3f x)\n",
+		name, theta.At(0, 0), theta.At(1, 0), plain.At(0, 0), plain.At(1, 0))
+}
+
+func write(sink util.Sink, p *plot.Plot) {
+	if err := sink.WritePlot(p, 400, 400); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// compare returns a scatter plot of the examples with the plain least squares
+// fit and the robust one. Examples with a false inliers value are circled.
+func compare(title string, X, y *mat.Dense, inliers []bool, plain, robust *mat.Dense) *plot.Plot {
+	p, _ := plot.New()
+	var in, out plotter.XYs
+	m, _ := X.Dims()
+	for i := 0; i < m; i++ {
+		xy := plotter.XY{X: X.At(i, 1), Y: y.At(i, 0)}
+		if inliers == nil || inliers[i] {
+			in = append(in, xy)
+		} else {
+			out = append(out, xy)
+		}
+	}
+	s, _ := plotter.NewScatter(in)
+	s.Color = color.RGBA{R: 255, A: 255}
+	s.Shape = draw.CrossGlyph{}
+	p.Add(s)
+	if len(out) > 0 {
+		s, _ := plotter.NewScatter(out)
+		s.Shape = draw.CircleGlyph{}
+		p.Add(s)
+		p.Legend.Add("outliers", s)
+	}
+
+	for _, l := range []struct {
+		name  string
+		theta *mat.Dense
+		color color.Color
+	}{
+		{"least squares", plain, color.Gray{128}},
+		{title, robust, color.RGBA{B: 255, A: 255}},
+	} {
+		theta := l.theta
+		f := plotter.NewFunction(func(x float64) float64 {
+			return theta.At(0, 0) + theta.At(1, 0)*x
+		})
+		f.Color = l.color
+		p.Add(f)
+		p.Legend.Add(l.name, f)
+	}
+	p.Legend.Top = true
+	p.Title.Text = title
+	p.X.Label.Text = "x"
+	p.Y.Label.Text = "y"
+	return p
+}
@@ -0,0 +1,108 @@
+package linreg
+
+import (
+	"math"
+	"testing"
+
+	"github.com/campoy/goml/datagen"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestWeighted(t *testing.T) {
+	// The last example is far from the line y = 1 + 2x of the others,
+	// so giving it a weight of zero recovers that line.
+	X, y, theta := InitParameters(mat.NewDense(5, 2, []float64{0, 1, 1, 3, 2, 5, 3, 7, 4, 30}))
+	w := []float64{1, 1, 1, 1, 0}
+
+	got, err := WeightedEquation(X, y, w)
+	if err != nil {
+		t.Fatalf("could not solve: %v", err)
+	}
+	if math.Abs(got.At(0, 0)-1) > 1e-9 || math.Abs(got.At(1, 0)-2) > 1e-9 {
+		t.Errorf("expected theta [1 2]; got %v", mat.Formatted(got.T()))
+	}
+	if cost, err := ComputeCostWeighted(X, y, got, w); err != nil || cost > 1e-18 {
+		t.Errorf("expected zero cost; got %v, %v", cost, err)
+	}
+	ones := []float64{1, 1, 1, 1, 1}
+	if got, err := ComputeCostWeighted(X, y, theta, ones); err != nil || got != ComputeCost(X, y, theta) {
+		t.Errorf("expected cost with unit weights to be %v; got %v, %v", ComputeCost(X, y, theta), got, err)
+	}
+
+	gd, _, costs, err := GradientDescentWeighted(X, y, mat.DenseCopyOf(theta), w, 0.1, 2000, nil)
+	if err != nil {
+		t.Fatalf("could not descend: %v", err)
+	}
+	if math.Abs(gd.At(0, 0)-1) > 1e-6 || math.Abs(gd.At(1, 0)-2) > 1e-6 {
+		t.Errorf("expected gradient descent to reach [1 2]; got %v", mat.Formatted(gd.T()))
+	}
+	if len(costs) != 2000 {
+		t.Errorf("expected 2000 costs; got %d", len(costs))
+	}
+
+	for _, bad := range [][]float64{{1}, {1, 1, 1, 1, -1}} {
+		if _, err := WeightedEquation(X, y, bad); err == nil {
+			t.Errorf("expected error solving with weights %v", bad)
+		}
+		if _, err := ComputeCostWeighted(X, y, theta, bad); err == nil {
+			t.Errorf("expected error computing the cost with weights %v", bad)
+		}
+		if _, _, _, err := GradientDescentWeighted(X, y, theta, bad, 0.1, 10, nil); err == nil {
+			t.Errorf("expected error descending with weights %v", bad)
+		}
+	}
+
+	if _, _, _, err := GradientDescentWeighted(X, y, mat.DenseCopyOf(theta), ones, 1e3, 100, nil); err == nil {
+		t.Errorf("expected a DivergenceError for a large alpha")
+	} else if _, ok := err.(DivergenceError); !ok {
+		t.Errorf("expected a DivergenceError; got %T", err)
+	}
+}
+
+func TestRobust(t *testing.T) {
+	// A tenth of the examples get errors of 5 to 10 standard deviations.
+	X, y, _ := InitParameters(datagen.Linear(200, []float64{1, 2}, 0.1, 0.1, 1))
+	ols, err := NormalEquation(X, y)
+	if err != nil {
+		t.Fatalf("could not solve: %v", err)
+	}
+	olsErr := math.Abs(ols.At(0, 0)-1) + math.Abs(ols.At(1, 0)-2)
+
+	huber, w, err := Huber(X, y, 1.345, 50, 1e-8)
+	if err != nil {
+		t.Fatalf("could not fit huber: %v", err)
+	}
+	ransac, inliers, err := RANSAC(X, y, RANSACOptions{Seed: 1})
+	if err != nil {
+		t.Fatalf("could not fit ransac: %v", err)
+	}
+
+	for name, theta := range map[string]*mat.Dense{"huber": huber, "ransac": ransac} {
+		got := math.Abs(theta.At(0, 0)-1) + math.Abs(theta.At(1, 0)-2)
+		if got > 0.1 || got > olsErr/2 {
+			t.Errorf("%s: expected an error much smaller than %v; got theta %v", name, olsErr, mat.Formatted(theta.T()))
+		}
+	}
+
+	outliers, downweighted := 0, 0
+	for i, in := range inliers {
+		if !in {
+			outliers++
+		}
+		if w[i] < 1 {
+			downweighted++
+		}
+	}
+	if outliers < 20 || outliers > 40 {
+		t.Errorf("expected around 20 outliers; got %d", outliers)
+	}
+	if downweighted < 20 {
+		t.Errorf("expected at least 20 examples with weight under 1; got %d", downweighted)
+	}
+
+	for _, n := range []int{-1, 201} {
+		if _, _, err := RANSAC(X, y, RANSACOptions{MinSamples: n}); err == nil {
+			t.Errorf("expected error for %d samples per candidate", n)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package linreg
+
+import (
+	"math"
+	"math/rand"
+
+	"gonum.org/v1/gonum/mat"
+
+	"github.com/campoy/goml/schedule"
+)
+
+// BatchOptions configures MiniBatchGradientDescent.
+type BatchOptions struct {
+	// BatchSize is the number of examples used in each step.
+	// A size of 1 is stochastic gradient descent, and a size of 0 or
+	// larger than the number of examples uses all of them in every step.
+	BatchSize int
+	// Epochs is the number of passes over all the examples.
+	Epochs int
+	// Seed is used to shuffle the examples at the beginning of every epoch,
+	// so the same seed always produces the same theta.
+	Seed int64
+	// Lambda is the regularization parameter, as in GradientDescentReg.
+	Lambda float64
+	// Schedule gives the learning rate of each step, counting the steps of
+	// all epochs. The alpha given to MiniBatchGradientDescent is used for
+	// every step when it is nil.
+	Schedule schedule.Schedule
+}
+
+// MiniBatchGradientDescent performs gradient descent to learn theta, taking a
+// step with learning rate alpha for each batch of examples of every epoch.
+//
+// Like GradientDescent, it returns the updated theta, together with the values
+// of theta after each epoch and the cost of each of them, as recorded by hist.
+// Costs are computed over all the examples, so they are only computed once per
+// recorded epoch. A nil hist records every epoch.
+func MiniBatchGradientDescent(X, y, theta *mat.Dense, alpha float64, opts BatchOptions, hist *History) (*mat.Dense, [][]float64, []float64) {
+	m, n := X.Dims()
+	_, k := y.Dims()
+	size := opts.BatchSize
+	if size <= 0 || size > m {
+		size = m
+	}
+	rng := rand.New(rand.NewSource(opts.Seed))
+	order := make([]int, m)
+	for i := range order {
+		order[i] = i
+	}
+	sched := opts.Schedule
+	if sched == nil {
+		sched = schedule.Constant(alpha)
+	}
+	if hist == nil {
+		hist = new(History)
+	}
+	xb := mat.NewDense(size, n, nil)
+	yb := mat.NewDense(size, k, nil)
+
+	step := 0
+	for epoch := 0; epoch < opts.Epochs; epoch++ {
+		rng.Shuffle(m, func(i, j int) { order[i], order[j] = order[j], order[i] })
+		for from := 0; from < m; from += size {
+			to := min(from+size, m)
+			bx, by := xb, yb
+			if to-from < size {
+				bx = xb.Slice(0, to-from, 0, n).(*mat.Dense)
+				by = yb.Slice(0, to-from, 0, k).(*mat.Dense)
+			}
+			for i, row := range order[from:to] {
+				bx.SetRow(i, X.RawRowView(row))
+				by.SetRow(i, y.RawRowView(row))
+			}
+
+			// gradient divides the penalty by the size of the batch instead of
+			// m, so it is scaled to keep the one of ComputeCostReg, and each
+			// batch gradient is an unbiased estimate of the full gradient.
+			grad := gradient(bx, by, theta, opts.Lambda*float64(to-from)/float64(m))
+			grad.Scale(sched.Rate(step), grad)
+			theta.Sub(theta, grad)
+			step++
+		}
+		cost := math.NaN()
+		if hist.due() {
+			cost = ComputeCostReg(X, y, theta, opts.Lambda)
+		}
+		hist.Record(theta, cost)
+	}
+
+	return theta, hist.Thetas(), hist.Costs()
+}
+
+// StochasticGradientDescent performs gradient descent to learn theta, taking
+// a step with learning rate alpha for every example, in a random order given
+// by seed, for the given number of epochs. It returns the same values as
+// MiniBatchGradientDescent.
+func StochasticGradientDescent(X, y, theta *mat.Dense, alpha float64, epochs int, seed int64, hist *History) (*mat.Dense, [][]float64, []float64) {
+	return MiniBatchGradientDescent(X, y, theta, alpha, BatchOptions{BatchSize: 1, Epochs: epochs, Seed: seed}, hist)
+}
@@ -0,0 +1,53 @@
+package linreg
+
+import (
+	"math"
+	"testing"
+
+	"github.com/campoy/goml/datagen"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestMiniBatchGradientDescent(t *testing.T) {
+	X, y, theta := InitParameters(datagen.Linear(100, []float64{2, -3}, 0, 0, 1))
+
+	tc := []struct {
+		name   string
+		alpha  float64
+		opts   BatchOptions
+		epochs int
+		within float64
+	}{
+		{"full batch", 0.5, BatchOptions{Epochs: 100}, 100, 1e-6},
+		{"mini batch", 0.1, BatchOptions{BatchSize: 16, Epochs: 50, Seed: 1}, 50, 1e-6},
+		{"stochastic", 0.05, BatchOptions{BatchSize: 1, Epochs: 20, Seed: 1}, 20, 1e-6},
+		{"ridge", 0.1, BatchOptions{BatchSize: 10, Epochs: 200, Lambda: 10}, 200, 5e-2},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			want, err := RidgeEquation(X, y, tt.opts.Lambda)
+			if err != nil {
+				t.Fatalf("could not solve: %v", err)
+			}
+			got, thetas, costs := MiniBatchGradientDescent(X, y, mat.DenseCopyOf(theta), tt.alpha, tt.opts, nil)
+			if len(thetas) != tt.epochs || len(costs) != tt.epochs {
+				t.Errorf("expected %d epochs; got %d thetas and %d costs", tt.epochs, len(thetas), len(costs))
+			}
+			if want := ComputeCostReg(X, y, got, tt.opts.Lambda); costs[len(costs)-1] != want {
+				t.Errorf("expected last cost to be the one of theta %v; got %v", want, costs[len(costs)-1])
+			}
+			for i := 0; i < 2; i++ {
+				if math.Abs(got.At(i, 0)-want.At(i, 0)) > tt.within {
+					t.Errorf("expected theta[%d] to be %v; got %v", i, want.At(i, 0), got.At(i, 0))
+				}
+			}
+		})
+	}
+
+	a, _, _ := StochasticGradientDescent(X, y, mat.DenseCopyOf(theta), 0.05, 1, 7, nil)
+	b, _, _ := StochasticGradientDescent(X, y, mat.DenseCopyOf(theta), 0.05, 1, 7, nil)
+	if !mat.Equal(a, b) {
+		t.Errorf("expected the same seed to give the same theta; got %v and %v", mat.Formatted(a.T()), mat.Formatted(b.T()))
+	}
+}
@@ -1,26 +1,30 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"image/color"
 	"log"
 	"math"
-	"os"
 
+	"github.com/campoy/goml/features"
+	"github.com/campoy/goml/iplot"
 	"github.com/campoy/goml/iplot/xyer"
+	"github.com/campoy/goml/schedule"
 	"github.com/campoy/goml/util"
 	"github.com/campoy/mat"
-	"github.com/campoy/tools/imgcat"
 	"gonum.org/v1/plot"
 	"gonum.org/v1/plot/plotter"
 	"gonum.org/v1/plot/vg/draw"
 )
 
 func main() {
-	enc, err := imgcat.NewEncoder(os.Stdout,
-		imgcat.Width(imgcat.Cells(100)), imgcat.Inline(true))
+	plots := flag.String("plots", "", "directory where plots are saved instead of shown")
+	flag.Parse()
+
+	sink, err := util.NewSink(*plots)
 	if err != nil {
-		fmt.Fprintf(os.Stdout, "images will not be shown: %v\n", err)
+		log.Fatal(err)
 	}
 
 	data, err := util.ParseMatrix("ex2data1.txt")
@@ -38,7 +42,9 @@ func main() {
 		"and o indicating (y = 0) examples.")
 
 	p := plotDataset(X, y)
-	util.PrintPlot(enc, p, 400, 400)
+	if err := sink.WritePlot(p, 400, 400); err != nil {
+		log.Fatal(err)
+	}
 
 	initialTheta := mat.New(n+1, 1)
 	cost, grad := costFunction(initialTheta, X, y)
@@ -65,7 +71,7 @@ func main() {
 	for i := 0; true; i++ {
 		theta = optimize(func(theta mat.Matrix) (float64, mat.Matrix) {
 			return costFunction(theta, X, y)
-		}, theta, 250000)
+		}, theta, schedule.Constant(0.0001), 250000)
 		if i == 0 && i%100 != 0 {
 			continue
 		}
@@ -79,7 +85,9 @@ func main() {
 		p.Add(line(theta))
 		p.X.Min, p.X.Max = 0, 100
 		p.Y.Min, p.Y.Max = 0, 100
-		util.PrintPlot(enc, p, 400, 400)
+		if err := sink.WritePlot(p, 400, 400); err != nil {
+			log.Fatal(err)
+		}
 	}
 
 	cost, _ = costFunction(theta, X, y)
@@ -90,7 +98,9 @@ func main() {
 	p.Add(line(theta))
 	p.X.Min, p.X.Max = 0, 100
 	p.Y.Min, p.Y.Max = 0, 100
-	util.PrintPlot(enc, p, 400, 400)
+	if err := sink.WritePlot(p, 400, 400); err != nil {
+		log.Fatal(err)
+	}
 
 	// For a student with scores 45 and 85, we predict an admission probability of 0.776289
 	prob := sigmoid(mat.Product(mat.FromSlice(1, 3, []float64{1, 45, 85}), theta).At(0, 0))
@@ -99,6 +109,45 @@ func main() {
 
 	fmt.Printf("Train accurracy: %f\n", accuracy(X, theta, y))
 
+	// The examples in ex2data2.txt are not linearly separable, but they are
+	// once the two features are expanded to all their products up to degree 6.
+	data, err = util.ParseMatrix("ex2data2.txt")
+	if err != nil {
+		log.Fatalf("could not parse ex2data2.txt: %v", err)
+	}
+	poly := features.Polynomial{Degree: 6}
+	X = util.Campoy(poly.Transform(data.SliceCols(0, 2)))
+	y = data.SliceCols(2, 3)
+	fmt.Printf("\nMapped 2 features into %d: %v\n", X.Cols(), poly.Names(2, nil))
+
+	// Instead of guessing a learning rate, try increasing ones from a
+	// fresh theta and use the one where the cost decreased the fastest.
+	rt := mat.New(X.Cols(), 1)
+	alphas, losses, err := schedule.RangeTest(1e-4, 100, 200, func(alpha float64) float64 {
+		_, grad := costFunction(rt, X, y)
+		rt = mat.Minus(rt, grad.Scale(alpha))
+		cost, _ := costFunction(rt, X, y)
+		return cost
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	lr, err := iplot.LearningRates(alphas, losses)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := sink.WritePlot(lr, 400, 400); err != nil {
+		log.Fatal(err)
+	}
+	alpha := schedule.Suggest(alphas, losses)
+	fmt.Printf("Using learning rate %f, decaying over time\n", alpha)
+
+	theta = optimize(func(theta mat.Matrix) (float64, mat.Matrix) {
+		return costFunction(theta, X, y)
+	}, mat.New(X.Cols(), 1), schedule.InverseTime{Alpha: alpha, Decay: 0.001}, 10000)
+	cost, _ = costFunction(theta, X, y)
+	fmt.Printf("Cost at theta found by optimization: %f\n", cost)
+	fmt.Printf("Train accurracy: %f\n", accuracy(X, theta, y))
 }
 
 func costFunction(theta, X, y mat.Matrix) (float64, mat.Matrix) {
@@ -118,12 +167,11 @@ func costFunction(theta, X, y mat.Matrix) (float64, mat.Matrix) {
 
 func sigmoid(z float64) float64 { return 1 / (1 + math.Exp(-z)) }
 
-func optimize(cost func(theta mat.Matrix) (float64, mat.Matrix), initialTheta mat.Matrix, iters int) mat.Matrix {
+func optimize(cost func(theta mat.Matrix) (float64, mat.Matrix), initialTheta mat.Matrix, sched schedule.Schedule, iters int) mat.Matrix {
 	theta := initialTheta
-	alpha := 0.0001
 	for i := 0; i < iters; i++ {
 		_, grad := cost(theta)
-		theta = mat.Minus(theta, grad.Scale(alpha))
+		theta = mat.Minus(theta, grad.Scale(sched.Rate(i)))
 	}
 	return theta
 }
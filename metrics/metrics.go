@@ -0,0 +1,208 @@
+// Package metrics evaluates the predictions of regression models, with
+// scores and with plots to diagnose the residuals of a fit.
+//
+// All the functions take the actual targets y and the predictions pred as
+// matrices of the same shape, and use all their values, so models with more
+// than one target are scored over all of them. They return an error when the
+// shapes differ or the score is undefined for the given values.
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	"gonum.org/v1/gonum/stat"
+
+	"github.com/campoy/goml/util"
+)
+
+// values returns the values of y and pred, in row-major order.
+// It fails if their shapes differ or they are empty.
+func values(y, pred util.Matrix) (ys, ps []float64, err error) {
+	r, c := y.Dims()
+	if pr, pc := pred.Dims(); pr != r || pc != c {
+		return nil, nil, errors.Errorf("shapes of targets %dx%d and predictions %dx%d differ", r, c, pr, pc)
+	}
+	if r*c == 0 {
+		return nil, nil, errors.New("no targets to score")
+	}
+	ys, ps = make([]float64, 0, r*c), make([]float64, 0, r*c)
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			ys = append(ys, y.At(i, j))
+			ps = append(ps, pred.At(i, j))
+		}
+	}
+	return ys, ps, nil
+}
+
+// Residuals returns the differences between the targets and the predictions.
+func Residuals(y, pred util.Matrix) ([]float64, error) {
+	ys, ps, err := values(y, pred)
+	if err != nil {
+		return nil, err
+	}
+	for i := range ys {
+		ys[i] -= ps[i]
+	}
+	return ys, nil
+}
+
+// MSE returns the mean squared error of the predictions.
+func MSE(y, pred util.Matrix) (float64, error) {
+	res, err := Residuals(y, pred)
+	if err != nil {
+		return 0, err
+	}
+	sum := 0.0
+	for _, r := range res {
+		sum += r * r
+	}
+	return sum / float64(len(res)), nil
+}
+
+// RMSE returns the root of the mean squared error of the predictions,
+// which is in the same units as the targets.
+func RMSE(y, pred util.Matrix) (float64, error) {
+	mse, err := MSE(y, pred)
+	return math.Sqrt(mse), err
+}
+
+// MAE returns the mean absolute error of the predictions.
+func MAE(y, pred util.Matrix) (float64, error) {
+	res, err := Residuals(y, pred)
+	if err != nil {
+		return 0, err
+	}
+	sum := 0.0
+	for _, r := range res {
+		sum += math.Abs(r)
+	}
+	return sum / float64(len(res)), nil
+}
+
+// MAPE returns the mean absolute percentage error of the predictions,
+// as a fraction rather than a percentage. It fails when any target is zero
+// and its prediction is not, since that error is not a fraction of anything.
+func MAPE(y, pred util.Matrix) (float64, error) {
+	ys, ps, err := values(y, pred)
+	if err != nil {
+		return 0, err
+	}
+	sum := 0.0
+	for i, v := range ys {
+		if v == ps[i] {
+			continue
+		}
+		if v == 0 {
+			return 0, errors.Errorf("target %d is zero, its percentage error is undefined", i)
+		}
+		sum += math.Abs((v - ps[i]) / v)
+	}
+	return sum / float64(len(ys)), nil
+}
+
+// R2 returns the coefficient of determination of the predictions: the
+// fraction of the variance of the targets explained by them. It is 1 for
+// perfect predictions, 0 for always predicting the mean, and can be negative.
+// It fails for constant targets, which have no variance to explain.
+func R2(y, pred util.Matrix) (float64, error) {
+	ys, ps, err := values(y, pred)
+	if err != nil {
+		return 0, err
+	}
+	mean := stat.Mean(ys, nil)
+	ssRes, ssTot := 0.0, 0.0
+	for i, v := range ys {
+		ssRes += (v - ps[i]) * (v - ps[i])
+		ssTot += (v - mean) * (v - mean)
+	}
+	if ssTot == 0 {
+		return 0, errors.New("targets have no variance")
+	}
+	return 1 - ssRes/ssTot, nil
+}
+
+// AdjustedR2 returns R2 adjusted for the number of features p used by the
+// model, not counting the intercept, so adding features that do not improve
+// the fit lowers it. It needs more than p+1 targets.
+func AdjustedR2(y, pred util.Matrix, p int) (float64, error) {
+	r, c := y.Dims()
+	n := r * c
+	if p < 0 || n-p-1 <= 0 {
+		return 0, errors.Errorf("cannot adjust R2 of %d targets for %d features", n, p)
+	}
+	r2, err := R2(y, pred)
+	if err != nil {
+		return 0, err
+	}
+	return 1 - (1-r2)*float64(n-1)/float64(n-p-1), nil
+}
+
+// ExplainedVariance returns one minus the variance of the residuals divided
+// by the variance of the targets. Unlike R2, it does not penalize predictions
+// that are off by a constant. It fails for constant targets.
+func ExplainedVariance(y, pred util.Matrix) (float64, error) {
+	ys, ps, err := values(y, pred)
+	if err != nil {
+		return 0, err
+	}
+	vy := stat.Variance(ys, nil)
+	if !(vy > 0) {
+		return 0, errors.New("targets have no variance")
+	}
+	for i := range ps {
+		ps[i] = ys[i] - ps[i]
+	}
+	return 1 - stat.Variance(ps, nil)/vy, nil
+}
+
+// A Report contains all the scores of a set of predictions.
+type Report struct {
+	MSE, RMSE, MAE, MAPE float64
+	R2, AdjustedR2       float64
+	ExplainedVariance    float64
+}
+
+// Regression returns the Report of the predictions of a model using p
+// features, not counting the intercept. It fails if any of the scores cannot
+// be computed.
+func Regression(y, pred util.Matrix, p int) (Report, error) {
+	var r Report
+	for _, s := range []struct {
+		dst *float64
+		f   func(y, pred util.Matrix) (float64, error)
+	}{
+		{&r.MSE, MSE},
+		{&r.RMSE, RMSE},
+		{&r.MAE, MAE},
+		{&r.MAPE, MAPE},
+		{&r.R2, R2},
+		{&r.AdjustedR2, func(y, pred util.Matrix) (float64, error) { return AdjustedR2(y, pred, p) }},
+		{&r.ExplainedVariance, ExplainedVariance},
+	} {
+		v, err := s.f(y, pred)
+		if err != nil {
+			return Report{}, err
+		}
+		*s.dst = v
+	}
+	return r, nil
+}
+
+func (r Report) String() string {
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "MSE\t%.4g\n", r.MSE)
+	fmt.Fprintf(w, "RMSE\t%.4g\n", r.RMSE)
+	fmt.Fprintf(w, "MAE\t%.4g\n", r.MAE)
+	fmt.Fprintf(w, "MAPE\t%.2f%%\n", 100*r.MAPE)
+	fmt.Fprintf(w, "R2\t%.4f\n", r.R2)
+	fmt.Fprintf(w, "adjusted R2\t%.4f\n", r.AdjustedR2)
+	fmt.Fprintf(w, "explained variance\t%.4f\n", r.ExplainedVariance)
+	w.Flush()
+	return buf.String()
+}
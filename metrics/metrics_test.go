@@ -0,0 +1,95 @@
+package metrics
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+
+	"github.com/campoy/goml/util"
+)
+
+func TestMetrics(t *testing.T) {
+	y := mat.NewDense(4, 1, []float64{1, 2, 4, 5})
+	pred := mat.NewDense(4, 1, []float64{2, 2, 3, 5})
+	adjusted := func(y, pred util.Matrix) (float64, error) { return AdjustedR2(y, pred, 1) }
+
+	// Residuals are -1, 0, 1, 0 and the targets have mean 3.
+	tc := []struct {
+		name   string
+		f      func(y, pred util.Matrix) (float64, error)
+		y      util.Matrix
+		pred   util.Matrix
+		want   float64
+		hasErr bool
+	}{
+		{"mse", MSE, y, pred, 0.5, false},
+		{"rmse", RMSE, y, pred, math.Sqrt(0.5), false},
+		{"mae", MAE, y, pred, 0.5, false},
+		{"mape", MAPE, y, pred, (1 + 0.25) / 4, false},
+		{"r2", R2, y, pred, 1 - 2.0/10, false},
+		{"adjusted r2", adjusted, y, pred, 1 - 0.2*3/2, false},
+		{"explained variance", ExplainedVariance, y, pred, 1 - 0.5/2.5, false},
+		{"perfect r2", R2, y, y, 1, false},
+		// Predictions off by a constant have a perfect explained variance.
+		{"shifted explained variance", ExplainedVariance, y, mat.NewDense(4, 1, []float64{2, 3, 5, 6}), 1, false},
+		{"shifted r2", R2, y, mat.NewDense(4, 1, []float64{2, 3, 5, 6}), 1 - 4.0/10, false},
+		{"exact zero target mape", MAPE, mat.NewDense(2, 1, []float64{0, 2}), mat.NewDense(2, 1, []float64{0, 1}), 0.25, false},
+		{"zero target mape", MAPE, mat.NewDense(1, 1, []float64{0}), mat.NewDense(1, 1, []float64{1}), 0, true},
+		{"mismatched shapes", MSE, y, mat.NewDense(2, 1, []float64{1, 2}), 0, true},
+		{"constant r2", R2, mat.NewDense(2, 1, []float64{3, 3}), mat.NewDense(2, 1, []float64{3, 4}), 0, true},
+		{"constant explained variance", ExplainedVariance, mat.NewDense(2, 1, []float64{3, 3}), mat.NewDense(2, 1, []float64{3, 4}), 0, true},
+		{"too few targets for adjusted r2", adjusted, mat.NewDense(2, 1, []float64{1, 2}), mat.NewDense(2, 1, []float64{1, 2}), 0, true},
+	}
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.f(tt.y, tt.pred)
+			if tt.hasErr {
+				if err == nil {
+					t.Errorf("expected error; got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want && math.Abs(got-tt.want) > 1e-12 {
+				t.Errorf("expected %v; got %v", tt.want, got)
+			}
+		})
+	}
+
+	r, err := Regression(y, pred, 1)
+	if err != nil {
+		t.Fatalf("could not score: %v", err)
+	}
+	if r.MSE != 0.5 || math.Abs(r.R2-0.8) > 1e-12 {
+		t.Errorf("expected report to contain the scores; got %+v", r)
+	}
+	if s := r.String(); !strings.Contains(s, "adjusted R2") {
+		t.Errorf("expected report to show adjusted R2; got %q", s)
+	}
+	if _, err := Regression(y, pred, 3); err == nil {
+		t.Errorf("expected error scoring 4 targets with 3 features")
+	}
+}
+
+func TestWritePlots(t *testing.T) {
+	y := mat.NewDense(5, 1, []float64{1, 2, 4, 5, 7})
+	pred := mat.NewDense(5, 1, []float64{2, 2, 3, 5, 6})
+
+	var sink util.BufferSink
+	if err := WritePlots(&sink, y, pred); err != nil {
+		t.Fatalf("could not write plots: %v", err)
+	}
+	if len(sink.Plots) != 4 {
+		t.Errorf("expected 4 plots; got %d", len(sink.Plots))
+	}
+	if _, err := QQPlot(y, y); err == nil {
+		t.Errorf("expected error for a Q-Q plot of a perfect fit")
+	}
+	if err := WritePlots(&sink, y, mat.NewDense(1, 1, []float64{1})); err == nil {
+		t.Errorf("expected error for predictions of another shape")
+	}
+}
@@ -0,0 +1,162 @@
+package metrics
+
+import (
+	"math"
+	"sort"
+
+	"github.com/pkg/errors"
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distuv"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg/draw"
+
+	"github.com/campoy/goml/util"
+)
+
+// ResidualsPlot returns a scatter plot of the residuals against the predicted
+// values, with a line at zero. Residuals of a good fit are scattered around
+// the line without any pattern, and with the same spread for all predictions.
+func ResidualsPlot(y, pred util.Matrix) (*plot.Plot, error) {
+	_, ps, err := values(y, pred)
+	if err != nil {
+		return nil, err
+	}
+	res, err := Residuals(y, pred)
+	if err != nil {
+		return nil, err
+	}
+	xys := make(plotter.XYs, len(res))
+	for i := range res {
+		xys[i].X, xys[i].Y = ps[i], res[i]
+	}
+	p, err := scatter(xys)
+	if err != nil {
+		return nil, err
+	}
+	p.Add(plotter.NewFunction(func(float64) float64 { return 0 }))
+	p.Title.Text = "Residuals vs fitted"
+	p.X.Label.Text = "fitted"
+	p.Y.Label.Text = "residual"
+	return p, nil
+}
+
+// ResidualsHistogram returns a histogram of the residuals with the given
+// number of bins.
+func ResidualsHistogram(y, pred util.Matrix, bins int) (*plot.Plot, error) {
+	res, err := Residuals(y, pred)
+	if err != nil {
+		return nil, err
+	}
+	p, err := plot.New()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create plot")
+	}
+	h, err := plotter.NewHist(plotter.Values(res), bins)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create histogram")
+	}
+	p.Add(h)
+	p.Title.Text = "Residuals"
+	p.Y.Label.Text = "count"
+	return p, nil
+}
+
+// QQPlot returns a normal Q-Q plot of the residuals: their standardized values
+// sorted against the quantiles of a standard normal distribution. The points
+// are close to the diagonal line when the residuals are normally distributed.
+func QQPlot(y, pred util.Matrix) (*plot.Plot, error) {
+	res, err := Residuals(y, pred)
+	if err != nil {
+		return nil, err
+	}
+	sort.Float64s(res)
+	mean, std := stat.MeanStdDev(res, nil)
+	if std == 0 || math.IsNaN(std) {
+		return nil, errors.New("residuals have no variance")
+	}
+	n := float64(len(res))
+	xys := make(plotter.XYs, len(res))
+	for i, r := range res {
+		xys[i].X = distuv.UnitNormal.Quantile((float64(i) + 0.5) / n)
+		xys[i].Y = (r - mean) / std
+	}
+	p, err := scatter(xys)
+	if err != nil {
+		return nil, err
+	}
+	p.Add(plotter.NewFunction(func(x float64) float64 { return x }))
+	p.Title.Text = "Normal Q-Q"
+	p.X.Label.Text = "theoretical quantiles"
+	p.Y.Label.Text = "standardized residuals"
+	return p, nil
+}
+
+// PredictedActual returns a scatter plot of the predictions against the
+// actual values, with the diagonal where the points of a perfect fit lie.
+func PredictedActual(y, pred util.Matrix) (*plot.Plot, error) {
+	ys, ps, err := values(y, pred)
+	if err != nil {
+		return nil, err
+	}
+	xys := make(plotter.XYs, len(ys))
+	for i := range ys {
+		xys[i].X, xys[i].Y = ys[i], ps[i]
+	}
+	p, err := scatter(xys)
+	if err != nil {
+		return nil, err
+	}
+	p.Add(plotter.NewFunction(func(x float64) float64 { return x }))
+	p.Title.Text = "Predicted vs actual"
+	p.X.Label.Text = "actual"
+	p.Y.Label.Text = "predicted"
+	return p, nil
+}
+
+func scatter(xys plotter.XYs) (*plot.Plot, error) {
+	p, err := plot.New()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create plot")
+	}
+	s, err := plotter.NewScatter(xys)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create scatter")
+	}
+	s.Shape = draw.CrossGlyph{}
+	p.Add(s)
+	return p, nil
+}
+
+// Diagnostics returns the residuals vs fitted plot, the residuals histogram,
+// the Q-Q plot and the predicted vs actual plot, in that order.
+func Diagnostics(y, pred util.Matrix) ([]*plot.Plot, error) {
+	var plots []*plot.Plot
+	for _, f := range []func(y, pred util.Matrix) (*plot.Plot, error){
+		ResidualsPlot,
+		func(y, pred util.Matrix) (*plot.Plot, error) { return ResidualsHistogram(y, pred, 20) },
+		QQPlot,
+		PredictedActual,
+	} {
+		p, err := f(y, pred)
+		if err != nil {
+			return nil, err
+		}
+		plots = append(plots, p)
+	}
+	return plots, nil
+}
+
+// WritePlots writes the plots returned by Diagnostics to the sink.
+func WritePlots(sink util.Sink, y, pred util.Matrix) error {
+	plots, err := Diagnostics(y, pred)
+	if err != nil {
+		return err
+	}
+	for _, p := range plots {
+		if err := sink.WritePlot(p, 400, 400); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -7,6 +7,11 @@ import (
 	"time"
 
 	"github.com/campoy/mat"
+	"github.com/pkg/errors"
+
+	"github.com/campoy/goml/dataset"
+	"github.com/campoy/goml/schedule"
+	"github.com/campoy/goml/util"
 )
 
 var matProduct = mat.Product
@@ -59,14 +64,21 @@ func HotDecode(m mat.Matrix) mat.Matrix {
 	})
 }
 
+// Fit learns theta with a constant learning rate of 0.01,
+// until the accuracy is perfect or ctx is done.
 func Fit(ctx context.Context, x, y mat.Matrix) mat.Matrix {
+	return FitSchedule(ctx, x, y, schedule.Constant(0.01))
+}
+
+// FitSchedule works like Fit, with the learning rates given by sched.
+func FitSchedule(ctx context.Context, x, y mat.Matrix, sched schedule.Schedule) mat.Matrix {
 	start := time.Now()
 
 	initialTheta := mat.New(x.Cols(), y.Cols())
 
 	theta := initialTheta
 
-	for {
+	for t := 0; ; t++ {
 		acc, _ := Accuracy(x, theta, y)
 		fmt.Printf("t: %v |  accurracy: %f\n", time.Since(start), acc)
 
@@ -82,8 +94,44 @@ func Fit(ctx context.Context, x, y mat.Matrix) mat.Matrix {
 
 		theta = optimize(func(theta mat.Matrix) (float64, mat.Matrix) {
 			return costFunction(theta, x, y)
-		}, theta, 1)
+		}, theta, sched.Rate(t), 1)
+	}
+}
+
+// FitBatches learns theta with mini-batch gradient descent, taking a step
+// per batch of b with the learning rate given by sched for the epoch, for the
+// given number of epochs or until ctx is done. Only a batch of examples is
+// held in memory at a time. It fails if an epoch has no batches, which
+// happens when b drops its last batch and has fewer examples than a batch.
+func FitBatches(ctx context.Context, b *dataset.Batches, epochs int, sched schedule.Schedule) (mat.Matrix, error) {
+	start := time.Now()
+
+	var theta mat.Matrix
+	for epoch := 0; epoch < epochs; epoch++ {
+		cost, n := 0.0, 0
+		for b.Next() {
+			x, y := util.Campoy(b.X()), util.Campoy(b.Y())
+			if theta.Rows() == 0 {
+				theta = mat.New(x.Cols(), y.Cols())
+			}
+			j, grad := costFunction(theta, x, y)
+			theta = mat.Minus(theta, grad.Scale(sched.Rate(epoch)))
+			cost += j * float64(x.Rows())
+			n += x.Rows()
+		}
+		b.Reset()
+		if n == 0 {
+			return mat.Matrix{}, errors.Errorf("no examples in epoch %d", epoch)
+		}
+		fmt.Printf("t: %v | epoch: %d | cost: %f\n", time.Since(start), epoch, cost/float64(n))
+
+		select {
+		case <-ctx.Done():
+			return theta, nil
+		default:
+		}
 	}
+	return theta, nil
 }
 
 func costFunction(theta, x, y mat.Matrix) (float64, mat.Matrix) {
@@ -101,9 +149,8 @@ func costFunction(theta, x, y mat.Matrix) (float64, mat.Matrix) {
 	return j, grad
 }
 
-func optimize(cost func(theta mat.Matrix) (float64, mat.Matrix), initialTheta mat.Matrix, iters int) mat.Matrix {
+func optimize(cost func(theta mat.Matrix) (float64, mat.Matrix), initialTheta mat.Matrix, alpha float64, iters int) mat.Matrix {
 	theta := initialTheta
-	alpha := 0.01
 	for i := 0; i < iters; i++ {
 		_, grad := cost(theta)
 		theta = mat.Minus(theta, grad.Scale(alpha))
@@ -1,10 +1,16 @@
 package logreg
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
 	"github.com/campoy/mat"
+	gmat "gonum.org/v1/gonum/mat"
+
+	"github.com/campoy/goml/dataset"
+	"github.com/campoy/goml/schedule"
+	"github.com/campoy/goml/util"
 )
 
 func TestHotDecode(t *testing.T) {
@@ -21,3 +27,41 @@ func TestHotEncode(t *testing.T) {
 	m := mat.FromSlice(4, 1, []float64{2, 1, 0, 1})
 	fmt.Println(HotEncode(m, 3))
 }
+
+func TestFitBatches(t *testing.T) {
+	// Two classes separated by the sign of the second feature.
+	x := gmat.NewDense(40, 2, nil)
+	y := gmat.NewDense(40, 2, nil)
+	for i := 0; i < 40; i++ {
+		v := float64(i%20+1) / 20
+		if i < 20 {
+			v = -v
+		}
+		x.SetRow(i, []float64{1, v})
+		if v > 0 {
+			y.Set(i, 1, 1)
+		} else {
+			y.Set(i, 0, 1)
+		}
+	}
+
+	b, err := dataset.NewBatches(dataset.DenseSource(x, y), 8, dataset.BatchOptions{Shuffle: true, Seed: 1})
+	if err != nil {
+		t.Fatalf("could not create batches: %v", err)
+	}
+	theta, err := FitBatches(context.Background(), b, 50, schedule.Constant(1))
+	if err != nil {
+		t.Fatalf("could not fit: %v", err)
+	}
+	if acc, missed := Accuracy(util.Campoy(x), theta, util.Campoy(y)); acc != 1 {
+		t.Errorf("expected perfect accuracy; got %v, missing %v", acc, missed)
+	}
+
+	b, err = dataset.NewBatches(dataset.DenseSource(x, y), 64, dataset.BatchOptions{DropLast: true})
+	if err != nil {
+		t.Fatalf("could not create batches: %v", err)
+	}
+	if _, err := FitBatches(context.Background(), b, 1, schedule.Constant(1)); err == nil {
+		t.Errorf("expected error fitting without full batches")
+	}
+}
@@ -7,8 +7,11 @@ import (
 	"os"
 	"time"
 
+	"github.com/campoy/goml/dataset"
 	"github.com/campoy/goml/mnist/logreg"
 	"github.com/campoy/goml/mnist/mnist"
+	"github.com/campoy/goml/schedule"
+	"github.com/campoy/goml/util"
 	"github.com/campoy/mat"
 	"github.com/campoy/tools/imgcat"
 )
@@ -16,6 +19,9 @@ import (
 func main() {
 	imagesPath := flag.String("i", "data/train-images-idx3-ubyte.gz", "path to the file containing all the images")
 	labelsPath := flag.String("l", "data/train-labels-idx1-ubyte.gz", "path to the file containing all the labels")
+	thetaPath := flag.String("o", "theta.bin", "path to the file where the trained theta is stored")
+	batch := flag.Int("batch", 256, "number of images in each mini-batch")
+	epochs := flag.Int("epochs", 10, "number of passes over the training images")
 	flag.Parse()
 
 	images, err := mnist.DecodeImages(*imagesPath)
@@ -29,57 +35,108 @@ func main() {
 		os.Exit(2)
 	}
 
-	theta := train(images, labels)
+	theta := train(images, labels, *batch, *epochs)
 	fmt.Println("storing theta:", theta.Rows(), theta.Cols())
+	if err := util.SaveBinary(*thetaPath, theta, util.Float64); err != nil {
+		fmt.Fprintf(os.Stderr, "could not store theta: %v\n", err)
+		os.Exit(1)
+	}
 }
 
-func train(images [][]byte, labels []byte) mat.Matrix {
+func train(images [][]byte, labels []byte, batch, epochs int) mat.Matrix {
 	enc, err := imgcat.NewEncoder(os.Stdout, imgcat.Width(imgcat.Percent(25)))
 	if err != nil {
-		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintf(os.Stderr, "images will not be shown: %v\n", err)
 	}
 
 	for i, img := range images[:10] {
 		fmt.Println("label:", labels[i])
-		mnist.PlotImage(enc, img)
+		show(enc, img)
 	}
 
 	fmt.Println("press enter to continue")
 	fmt.Scanln()
 
-	m := len(images)
-	n := len(images[0])
-	k := 10
-
-	x := mat.FromFunc(m, n, func(i, j int) float64 { return float64(images[i][j])/255 + 0.5 })
-	x = mat.ConcatenateCols(mat.New(m, 1).AddScalar(1), x)
-
-	// // 😇
-	// m = 100
-	// x = x.SliceRows(0, m)
-
-	y := mat.FromFunc(m, k, func(i, j int) float64 {
-		if labels[i] == byte(j) {
-			return 1
-		}
-		return 0
-	})
+	split, err := dataset.ShuffleSplit(len(images), 0, 0.2, 1)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "could not split: %v\n", err)
+		os.Exit(1)
+	}
+	trainSrc, testSrc := subset(images, labels, split.Train), subset(images, labels, split.Test)
 
+	// Images are converted to float64 one batch at a time,
+	// instead of holding a float64 copy of the whole dataset.
+	b, err := dataset.NewBatches(trainSrc, batch, dataset.BatchOptions{Shuffle: true, Seed: 1})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "could not create batches: %v\n", err)
+		os.Exit(1)
+	}
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
-	theta := logreg.Fit(ctx, x, y)
+	theta, err := logreg.FitBatches(ctx, b, epochs, schedule.Constant(0.1))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "could not fit: %v\n", err)
+		os.Exit(1)
+	}
 
-	acc, missed := logreg.Accuracy(x, theta, y)
+	acc, _ := accuracy(trainSrc, theta, batch)
 	fmt.Printf("Train accurracy: %f\n", acc)
+	acc, missed := accuracy(testSrc, theta, batch)
+	fmt.Printf("Test accurracy: %f\n", acc)
 
 	fmt.Println("misspredicted")
 	for _, i := range missed {
-		fmt.Println("label:", labels[i])
-		pred := logreg.HotDecode(
-			logreg.Predict(x.SliceRows(i, i+1), theta))
+		fmt.Println("label:", testSrc.Labels[i])
+		pred := logreg.HotDecode(logreg.Predict(row(testSrc, i), theta))
 		fmt.Println("predicted:", int(pred.At(0, 0)))
-		mnist.PlotImage(enc, images[i])
+		show(enc, testSrc.Images[i])
 	}
 
-	return mat.Matrix{}
+	return theta
+}
+
+// show writes the image to enc, unless images cannot be shown.
+func show(enc *imgcat.Encoder, img []byte) {
+	if enc == nil {
+		return
+	}
+	if err := mnist.PlotImage(enc, img); err != nil {
+		fmt.Fprintf(os.Stderr, "could not show image: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// subset returns a source with the images and labels at the given indices.
+func subset(images [][]byte, labels []byte, idx []int) mnist.Source {
+	src := mnist.Source{Images: make([][]byte, len(idx)), Labels: make([]byte, len(idx))}
+	for i, j := range idx {
+		src.Images[i], src.Labels[i] = images[j], labels[j]
+	}
+	return src
+}
+
+// row returns the ith row of features of src as a matrix with one row.
+func row(src mnist.Source, i int) mat.Matrix {
+	xc, yc := src.Dims()
+	x := make([]float64, xc)
+	src.Row(i, x, make([]float64, yc))
+	return mat.FromSlice(1, xc, x)
+}
+
+// accuracy computes the accuracy of theta predicting the labels of src, one
+// batch at a time, and returns the indices of the misspredicted images.
+func accuracy(src mnist.Source, theta mat.Matrix, batch int) (float64, []int) {
+	b, err := dataset.NewBatches(src, batch, dataset.BatchOptions{})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "could not create batches: %v\n", err)
+		os.Exit(1)
+	}
+	var missed []int
+	for b.Next() {
+		_, m := logreg.Accuracy(util.Campoy(b.X()), theta, util.Campoy(b.Y()))
+		for _, i := range m {
+			missed = append(missed, b.Index()[i])
+		}
+	}
+	return 1 - float64(len(missed))/float64(src.Len()), missed
 }
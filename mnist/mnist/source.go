@@ -0,0 +1,37 @@
+package mnist
+
+// A Source provides the decoded images and labels as rows of float64 values,
+// converting each image only when it is requested. It can be used as a
+// dataset.RowSource to iterate over mini-batches without holding a float64
+// copy of every image in memory.
+//
+// The features of each row are a 1 for the bias term followed by the pixels
+// scaled as float64(p)/255 + 0.5, and the targets are the hot encoded label.
+type Source struct {
+	Images [][]byte
+	Labels []byte
+}
+
+// Len returns the number of images.
+func (s Source) Len() int { return len(s.Images) }
+
+// Dims returns the number of features and targets of each row. The number
+// of features is 0 when there are no images to count their pixels.
+func (s Source) Dims() (x, y int) {
+	if len(s.Images) == 0 {
+		return 0, 10
+	}
+	return len(s.Images[0]) + 1, 10
+}
+
+// Row writes the features and targets of the ith image into x and y.
+func (s Source) Row(i int, x, y []float64) {
+	x[0] = 1
+	for j, p := range s.Images[i] {
+		x[j+1] = float64(p)/255 + 0.5
+	}
+	for j := range y {
+		y[j] = 0
+	}
+	y[s.Labels[i]] = 1
+}
@@ -0,0 +1,56 @@
+package schedule
+
+import (
+	"math"
+
+	"github.com/pkg/errors"
+)
+
+// RangeTest runs a learning rate range test: it takes the given number of
+// steps of gradient descent with learning rates increasing geometrically from
+// min to max, and returns the learning rate and loss of each step.
+//
+// The step function takes one step with the given learning rate from the
+// current parameters, and returns the loss after it. The test stops early
+// once the loss is NaN, infinite or four times larger than the best one.
+//
+// A good constant learning rate is usually an order of magnitude smaller than
+// the one with the lowest loss, where the loss is still decreasing fastest.
+func RangeTest(min, max float64, steps int, step func(alpha float64) float64) (alphas, losses []float64, err error) {
+	if min <= 0 || max <= min {
+		return nil, nil, errors.Errorf("expected 0 < min < max, got min %v and max %v", min, max)
+	}
+	if steps < 2 {
+		return nil, nil, errors.Errorf("expected at least 2 steps, got %d", steps)
+	}
+	factor := math.Pow(max/min, 1/float64(steps-1))
+	best := math.Inf(1)
+	alpha := min
+	for i := 0; i < steps; i++ {
+		loss := step(alpha)
+		alphas = append(alphas, alpha)
+		losses = append(losses, loss)
+		if math.IsNaN(loss) || math.IsInf(loss, 0) || loss > 4*best {
+			break
+		}
+		best = math.Min(best, loss)
+		alpha *= factor
+	}
+	return alphas, losses, nil
+}
+
+// Suggest returns the learning rate where the loss decreased the fastest
+// in the results of RangeTest, with respect to the log of the learning rate.
+func Suggest(alphas, losses []float64) float64 {
+	best, steepest := alphas[0], math.Inf(1)
+	for i := 1; i < len(losses); i++ {
+		if math.IsNaN(losses[i]) || math.IsInf(losses[i], 0) {
+			break
+		}
+		slope := (losses[i] - losses[i-1]) / math.Log(alphas[i]/alphas[i-1])
+		if slope < steepest {
+			best, steepest = alphas[i], slope
+		}
+	}
+	return best
+}
@@ -0,0 +1,100 @@
+// Package schedule provides learning rate schedules for gradient descent,
+// and a range test to choose the learning rate instead of guessing it.
+package schedule
+
+import "math"
+
+// A Schedule gives the learning rate for each step of gradient descent.
+type Schedule interface {
+	// Rate returns the learning rate for step t, starting at 0.
+	Rate(t int) float64
+}
+
+// Constant uses the same learning rate for every step.
+type Constant float64
+
+// Rate returns c.
+func (c Constant) Rate(t int) float64 { return float64(c) }
+
+// StepDecay multiplies the learning rate by Drop every Every steps.
+// An Every smaller than 1 is treated as 1.
+type StepDecay struct {
+	Alpha float64 // Initial learning rate.
+	Drop  float64
+	Every int
+}
+
+// Rate returns Alpha * Drop^(t/Every), with integer division.
+func (s StepDecay) Rate(t int) float64 {
+	return s.Alpha * math.Pow(s.Drop, float64(t/max(s.Every, 1)))
+}
+
+// Exponential multiplies the learning rate by Decay on every step.
+type Exponential struct {
+	Alpha float64 // Initial learning rate.
+	Decay float64
+}
+
+// Rate returns Alpha * Decay^t.
+func (s Exponential) Rate(t int) float64 { return s.Alpha * math.Pow(s.Decay, float64(t)) }
+
+// InverseTime decreases the learning rate proportionally to the inverse of the
+// number of steps, which satisfies the conditions for stochastic gradient
+// descent to converge.
+type InverseTime struct {
+	Alpha float64 // Initial learning rate.
+	Decay float64
+}
+
+// Rate returns Alpha / (1 + Decay*t).
+func (s InverseTime) Rate(t int) float64 { return s.Alpha / (1 + s.Decay*float64(t)) }
+
+// Cosine anneals the learning rate from Alpha to Min following half a cosine
+// period over Steps steps, and keeps it at Min afterwards.
+type Cosine struct {
+	Alpha, Min float64
+	Steps      int
+}
+
+// Rate returns the annealed learning rate for step t.
+func (s Cosine) Rate(t int) float64 {
+	if t >= s.Steps {
+		return s.Min
+	}
+	return anneal(s.Alpha, s.Min, float64(t)/float64(s.Steps))
+}
+
+// WarmRestarts anneals the learning rate like Cosine, but restarts from Alpha
+// at the end of every period. The first period lasts Period steps, and every
+// period is Mult times longer than the previous one, as in SGDR. A Period
+// smaller than 1 and a Mult smaller than 1 are treated as 1.
+type WarmRestarts struct {
+	Alpha, Min float64
+	Period     int
+	Mult       float64
+}
+
+// Rate returns the annealed learning rate for step t within its period.
+func (s WarmRestarts) Rate(t int) float64 {
+	period := float64(max(s.Period, 1))
+	pos := float64(t)
+	if s.Mult <= 1 {
+		pos = math.Mod(pos, period)
+	}
+	for pos >= period {
+		pos -= period
+		period *= s.Mult
+	}
+	return anneal(s.Alpha, s.Min, pos/period)
+}
+
+// anneal returns the rate between max and min at fraction f of a half cosine.
+func anneal(max, min, f float64) float64 {
+	return min + (max-min)*(1+math.Cos(math.Pi*f))/2
+}
+
+// Func adapts a function to the Schedule interface.
+type Func func(t int) float64
+
+// Rate returns f(t).
+func (f Func) Rate(t int) float64 { return f(t) }
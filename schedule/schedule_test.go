@@ -0,0 +1,65 @@
+package schedule
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSchedules(t *testing.T) {
+	tc := []struct {
+		name  string
+		sched Schedule
+		rates map[int]float64
+	}{
+		{"constant", Constant(0.1), map[int]float64{0: 0.1, 1000: 0.1}},
+		{"step decay", StepDecay{Alpha: 1, Drop: 0.5, Every: 10}, map[int]float64{0: 1, 9: 1, 10: 0.5, 25: 0.25}},
+		{"exponential", Exponential{Alpha: 2, Decay: 0.5}, map[int]float64{0: 2, 1: 1, 3: 0.25}},
+		{"inverse time", InverseTime{Alpha: 1, Decay: 0.5}, map[int]float64{0: 1, 2: 0.5, 6: 0.25}},
+		{"cosine", Cosine{Alpha: 1, Min: 0.2, Steps: 10}, map[int]float64{0: 1, 5: 0.6, 10: 0.2, 20: 0.2}},
+		{"warm restarts", WarmRestarts{Alpha: 1, Period: 10}, map[int]float64{0: 1, 5: 0.5, 10: 1, 15: 0.5}},
+		{"longer restarts", WarmRestarts{Alpha: 1, Period: 10, Mult: 2}, map[int]float64{10: 1, 20: 0.5, 30: 1}},
+		{"step decay every 0", StepDecay{Alpha: 1, Drop: 0.5}, map[int]float64{0: 1, 1: 0.5, 3: 0.125}},
+		{"restarts period 0", WarmRestarts{Alpha: 1}, map[int]float64{0: 1, 7: 1, 1 << 40: 1}},
+		{"restarts negative period", WarmRestarts{Alpha: 1, Period: -5, Mult: 2}, map[int]float64{0: 1, 1: 1, 2: 0.5, 3: 1}},
+		{"func", Func(func(t int) float64 { return float64(t) }), map[int]float64{3: 3}},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			for step, want := range tt.rates {
+				if got := tt.sched.Rate(step); math.Abs(got-want) > 1e-12 {
+					t.Errorf("expected rate %v at step %d; got %v", want, step, got)
+				}
+			}
+		})
+	}
+}
+
+func TestRangeTest(t *testing.T) {
+	// Gradient descent on x^2 decreases the loss for learning rates under 1,
+	// and diverges for larger ones.
+	x := 1.0
+	alphas, losses, err := RangeTest(1e-3, 10, 100, func(alpha float64) float64 {
+		x -= alpha * 2 * x
+		return x * x
+	})
+	if err != nil {
+		t.Fatalf("could not run range test: %v", err)
+	}
+	if len(alphas) != len(losses) {
+		t.Fatalf("expected as many alphas as losses; got %d and %d", len(alphas), len(losses))
+	}
+	if alphas[0] != 1e-3 {
+		t.Errorf("expected first learning rate to be 1e-3; got %v", alphas[0])
+	}
+	if last := alphas[len(alphas)-1]; len(alphas) == 100 || last < 1 {
+		t.Errorf("expected to stop early after diverging over 1; stopped at %v after %d steps", last, len(alphas))
+	}
+	if got := Suggest(alphas, losses); got < 0.01 || got > 1 {
+		t.Errorf("expected suggested learning rate between 0.01 and 1; got %v", got)
+	}
+
+	if _, _, err := RangeTest(1, 0.1, 10, nil); err == nil {
+		t.Errorf("expected error when max is smaller than min")
+	}
+}
@@ -0,0 +1,51 @@
+package util
+
+import (
+	"github.com/campoy/mat"
+	gmat "gonum.org/v1/gonum/mat"
+)
+
+// Gonum returns a view of m that satisfies gonum's mat.Matrix, so it can be
+// passed to functions such as linreg.InitParameters without copying it.
+func Gonum(m mat.Matrix) gmat.Matrix { return gonumView{m} }
+
+type gonumView struct{ m mat.Matrix }
+
+func (v gonumView) Dims() (int, int)    { return v.m.Dims() }
+func (v gonumView) At(i, j int) float64 { return v.m.At(i, j) }
+func (v gonumView) T() gmat.Matrix      { return gmat.Transpose{Matrix: v} }
+
+// Campoy returns m as a campoy matrix. When m is a *gmat.Dense whose rows
+// are contiguous, the result shares its backing data instead of copying it.
+func Campoy(m Matrix) mat.Matrix {
+	switch m := m.(type) {
+	case mat.Matrix:
+		return m
+	case gonumView:
+		return m.m
+	case *gmat.Dense:
+		raw := m.RawMatrix()
+		if raw.Stride == raw.Cols {
+			return mat.FromSlice(raw.Rows, raw.Cols, raw.Data[:raw.Rows*raw.Cols])
+		}
+	}
+	r, c := m.Dims()
+	return mat.FromFunc(r, c, m.At)
+}
+
+// Dense returns m as a gonum *mat.Dense. The result is m itself when it
+// already is a *mat.Dense, and a copy of its values otherwise.
+func Dense(m Matrix) *gmat.Dense {
+	if d, ok := m.(*gmat.Dense); ok {
+		return d
+	}
+	r, c := m.Dims()
+	d := gmat.NewDense(r, c, nil)
+	for i := 0; i < r; i++ {
+		row := d.RawRowView(i)
+		for j := range row {
+			row[j] = m.At(i, j)
+		}
+	}
+	return d
+}
@@ -0,0 +1,39 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/campoy/mat"
+	gmat "gonum.org/v1/gonum/mat"
+)
+
+func TestBridge(t *testing.T) {
+	c := mat.FromSlice(2, 3, []float64{1, 2, 3, 4, 5, 6})
+
+	g := Gonum(c)
+	if !gmat.Equal(g, gmat.NewDense(2, 3, []float64{1, 2, 3, 4, 5, 6})) {
+		t.Errorf("expected gonum view to have the same values; got %v", gmat.Formatted(g))
+	}
+	if got := g.T().At(2, 1); got != 6 {
+		t.Errorf("expected transposed view at [2, 1] to be 6; got %v", got)
+	}
+
+	d := Dense(g)
+	back := Campoy(d)
+	for i := 0; i < 2; i++ {
+		for j := 0; j < 3; j++ {
+			if back.At(i, j) != c.At(i, j) {
+				t.Errorf("expected round trip at [%d, %d] to be %v; got %v", i, j, c.At(i, j), back.At(i, j))
+			}
+		}
+	}
+	if Dense(d) != d {
+		t.Errorf("expected Dense to return a *mat.Dense as is")
+	}
+
+	// A column view is not contiguous, so it has to be copied.
+	col := Campoy(d.ColView(1))
+	if r, cols := col.Dims(); r != 2 || cols != 1 || col.At(1, 0) != 5 {
+		t.Errorf("expected column [2 5]; got %dx%d matrix", r, cols)
+	}
+}
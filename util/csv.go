@@ -0,0 +1,334 @@
+package util
+
+import (
+	"encoding/csv"
+	"io"
+	"math"
+	"os"
+	"strconv"
+
+	"github.com/campoy/mat"
+	"github.com/pkg/errors"
+)
+
+// CSVOptions configures how a CSV file is turned into a Table.
+// Columns are referred to by header name or by zero-based index.
+type CSVOptions struct {
+	// Comma is the field delimiter. It defaults to ','.
+	Comma rune
+	// Header indicates that the first record contains the column names.
+	Header bool
+	// Columns lists the columns to load into X, in order.
+	// All columns other than Target are loaded when it is empty.
+	Columns []string
+	// Drop lists columns that should not be loaded.
+	Drop []string
+	// Target is the column loaded into Y. When empty Y is left empty.
+	Target string
+
+	// Missing lists the tokens that denote a missing value, such as "" or "NA".
+	Missing []string
+	// Impute is the strategy used to handle missing values.
+	Impute MissingStrategy
+	// Fill is the value used by the ImputeConstant strategy.
+	Fill float64
+	// Indicator appends a column to X for every feature with missing
+	// values, set to 1 on the rows where the value was missing.
+	Indicator bool
+
+	// Categorical maps columns holding string values to the Encoder used
+	// to turn them into numeric columns. Encoders without categories are
+	// fitted on the loaded values, while fitted ones are applied as they
+	// are, so the encoding learned on training data can be reused on new
+	// data by passing the encoders returned in Table.Encoders. The given
+	// encoders are not modified. Missing tokens in these columns are
+	// treated as categories.
+	Categorical map[string]*Encoder
+}
+
+// A Table contains the features and target parsed from a CSV file.
+type Table struct {
+	Names  []string // Names of the columns in X.
+	X      mat.Matrix
+	Target string // Name of the column in Y.
+	Y      mat.Matrix
+
+	// Encoders holds the fitted encoder used for each categorical column,
+	// by name. They are copies of the ones given in CSVOptions.
+	Encoders map[string]*Encoder
+
+	// Mask has a column per loaded CSV column, before categorical encoding,
+	// and is 1 where the value was missing and 0 otherwise.
+	Mask mat.Matrix
+	// Fills holds the value imputed in each column of Mask,
+	// or NaN when rows with missing values were dropped.
+	Fills []float64
+}
+
+// Data returns X with Y appended as its last column, which is the layout
+// returned by ParseMatrix and expected by linreg.InitParameters.
+func (t *Table) Data() mat.Matrix {
+	if t.Y.Cols() == 0 {
+		return t.X
+	}
+	return mat.ConcatenateCols(t.X, t.Y)
+}
+
+// ParseCSV parses the CSV encoded file at path following the given options.
+func ParseCSV(path string, opts CSVOptions) (*Table, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not read %s", path)
+	}
+	defer f.Close()
+
+	t, err := ReadCSV(f, opts)
+	return t, errors.Wrapf(err, "could not parse %s", path)
+}
+
+// ReadCSV reads CSV records from r following the given options.
+// Parsing errors report the line and column of the offending cell.
+func ReadCSV(r io.Reader, opts CSVOptions) (*Table, error) {
+	cr := csv.NewReader(r)
+	if opts.Comma != 0 {
+		cr.Comma = opts.Comma
+	}
+
+	rec, err := cr.Read()
+	if err == io.EOF {
+		return nil, errors.New("no records found")
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read records")
+	}
+
+	names := make([]string, len(rec))
+	if opts.Header {
+		copy(names, rec)
+	} else {
+		for i := range names {
+			names[i] = strconv.Itoa(i)
+		}
+	}
+
+	cols, target, err := selectColumns(names, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	enc := make(map[int]*Encoder)
+	for key, e := range opts.Categorical {
+		col, err := columnIndex(names, key)
+		if err != nil {
+			return nil, errors.Wrap(err, "bad categorical column")
+		}
+		c := *e
+		c.Categories = append([]string(nil), e.Categories...)
+		enc[col] = &c
+	}
+
+	missing := make(map[string]bool)
+	for _, tok := range opts.Missing {
+		missing[tok] = true
+	}
+
+	var xs, ys []float64
+	strs := make(map[int][]string)
+	rows := 0
+	parse := func(rec []string, col int) (float64, error) {
+		if _, ok := enc[col]; ok {
+			strs[col] = append(strs[col], rec[col])
+			return 0, nil
+		}
+		line, _ := cr.FieldPos(col)
+		if missing[rec[col]] {
+			if opts.Impute == MissingError {
+				return 0, errors.Errorf("missing value at line %d, column %d (%s)", line, col+1, names[col])
+			}
+			return math.NaN(), nil
+		}
+		x, err := strconv.ParseFloat(rec[col], 64)
+		if err != nil {
+			return 0, errors.Wrapf(err, "could not parse float %q at line %d, column %d (%s)",
+				rec[col], line, col+1, names[col])
+		}
+		return x, nil
+	}
+
+	for {
+		if rows > 0 || opts.Header {
+			rec, err = cr.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, errors.Wrap(err, "could not read records")
+			}
+		}
+		for _, col := range cols {
+			x, err := parse(rec, col)
+			if err != nil {
+				return nil, err
+			}
+			xs = append(xs, x)
+		}
+		if target >= 0 {
+			y, err := parse(rec, target)
+			if err != nil {
+				return nil, err
+			}
+			ys = append(ys, y)
+		}
+		rows++
+	}
+
+	loaded := make([]string, len(cols))
+	for i, col := range cols {
+		loaded[i] = names[col]
+	}
+
+	t := &Table{}
+	if target >= 0 {
+		t.Target = names[target]
+	}
+
+	var mask []float64
+	if len(missing) == 0 || opts.Impute == MissingError {
+		mask = make([]float64, len(xs))
+	} else {
+		keep := keptRows(xs, ys, rows, len(cols), opts)
+		xs = takeRows(xs, len(cols), keep)
+		if target >= 0 {
+			ys = takeRows(ys, 1, keep)
+		}
+		for col, s := range strs {
+			kept := make([]string, len(keep))
+			for i, row := range keep {
+				kept[i] = s[row]
+			}
+			strs[col] = kept
+		}
+		rows = len(keep)
+
+		if mask, t.Fills, err = impute(xs, loaded, opts); err != nil {
+			return nil, err
+		}
+	}
+	t.Mask = mat.FromSlice(rows, len(cols), mask)
+
+	t.Encoders = make(map[string]*Encoder)
+	for col, e := range enc {
+		if len(e.Categories) == 0 {
+			e.Fit(strs[col])
+		}
+		t.Encoders[names[col]] = e
+	}
+
+	var ind []int
+	if opts.Indicator {
+		ind = indicatorColumns(mask, len(cols))
+	}
+
+	width := len(ind)
+	for _, col := range cols {
+		if e, ok := enc[col]; ok {
+			width += e.Width()
+			t.Names = append(t.Names, e.Names(names[col])...)
+		} else {
+			width++
+			t.Names = append(t.Names, names[col])
+		}
+	}
+	for _, j := range ind {
+		t.Names = append(t.Names, loaded[j]+"_missing")
+	}
+
+	data := make([]float64, 0, rows*width)
+	for i := 0; i < rows; i++ {
+		for j, col := range cols {
+			e, ok := enc[col]
+			if !ok {
+				data = append(data, xs[i*len(cols)+j])
+				continue
+			}
+			n := len(data)
+			data = append(data, make([]float64, e.Width())...)
+			if err := e.Encode(strs[col][i], data[n:]); err != nil {
+				return nil, errors.Wrapf(err, "could not encode column %s", names[col])
+			}
+		}
+		for _, j := range ind {
+			data = append(data, mask[i*len(cols)+j])
+		}
+	}
+	t.X = mat.FromSlice(rows, width, data)
+
+	if target >= 0 {
+		if e, ok := enc[target]; ok {
+			if t.Y, err = e.Transform(strs[target]); err != nil {
+				return nil, errors.Wrapf(err, "could not encode target %s", t.Target)
+			}
+		} else {
+			t.Y = mat.FromSlice(rows, 1, ys)
+		}
+	}
+	return t, nil
+}
+
+// selectColumns returns the indices of the columns to load into X
+// and the index of the target column, or -1 if there is none.
+func selectColumns(names []string, opts CSVOptions) (cols []int, target int, err error) {
+	target = -1
+	if opts.Target != "" {
+		if target, err = columnIndex(names, opts.Target); err != nil {
+			return nil, -1, errors.Wrap(err, "bad target")
+		}
+	}
+
+	dropped := make(map[int]bool)
+	for _, key := range opts.Drop {
+		col, err := columnIndex(names, key)
+		if err != nil {
+			return nil, -1, errors.Wrap(err, "bad dropped column")
+		}
+		dropped[col] = true
+	}
+	if target >= 0 && dropped[target] {
+		return nil, -1, errors.Errorf("target column %q is dropped", opts.Target)
+	}
+
+	if len(opts.Columns) == 0 {
+		for col := range names {
+			if col != target && !dropped[col] {
+				cols = append(cols, col)
+			}
+		}
+		return cols, target, nil
+	}
+
+	for _, key := range opts.Columns {
+		col, err := columnIndex(names, key)
+		if err != nil {
+			return nil, -1, errors.Wrap(err, "bad selected column")
+		}
+		if col != target && !dropped[col] {
+			cols = append(cols, col)
+		}
+	}
+	return cols, target, nil
+}
+
+// columnIndex finds the column with the given name, or interprets key as
+// a zero-based index when no column has that name.
+func columnIndex(names []string, key string) (int, error) {
+	for i, name := range names {
+		if name == key {
+			return i, nil
+		}
+	}
+	i, err := strconv.Atoi(key)
+	if err != nil || i < 0 || i >= len(names) {
+		return 0, errors.Errorf("unknown column %q", key)
+	}
+	return i, nil
+}
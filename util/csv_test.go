@@ -0,0 +1,189 @@
+package util
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReadCSV(t *testing.T) {
+	const data = "id,size,rooms,price\n1,2104,3,399900\n2,1600,3,329900\n3,2400,4,369000\n"
+
+	tc := []struct {
+		name   string
+		in     string
+		opts   CSVOptions
+		names  []string
+		x      [][]float64
+		target string
+		y      []float64
+	}{
+		{
+			name:  "no header",
+			in:    "1,2\n3,4\n",
+			names: []string{"0", "1"},
+			x:     [][]float64{{1, 2}, {3, 4}},
+		},
+		{
+			name:   "header with target and dropped id",
+			in:     data,
+			opts:   CSVOptions{Header: true, Drop: []string{"id"}, Target: "price"},
+			names:  []string{"size", "rooms"},
+			x:      [][]float64{{2104, 3}, {1600, 3}, {2400, 4}},
+			target: "price",
+			y:      []float64{399900, 329900, 369000},
+		},
+		{
+			name:   "selected columns by name and index",
+			in:     data,
+			opts:   CSVOptions{Header: true, Columns: []string{"2", "size"}, Target: "3"},
+			names:  []string{"rooms", "size"},
+			x:      [][]float64{{3, 2104}, {3, 1600}, {4, 2400}},
+			target: "price",
+			y:      []float64{399900, 329900, 369000},
+		},
+		{
+			name:  "semicolon separated",
+			in:    "a;b\n1;2\n",
+			opts:  CSVOptions{Comma: ';', Header: true},
+			names: []string{"a", "b"},
+			x:     [][]float64{{1, 2}},
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			tab, err := ReadCSV(strings.NewReader(tt.in), tt.opts)
+			if err != nil {
+				t.Fatalf("could not read csv: %v", err)
+			}
+			if strings.Join(tab.Names, ",") != strings.Join(tt.names, ",") {
+				t.Errorf("expected names %v; got %v", tt.names, tab.Names)
+			}
+			if tab.Target != tt.target {
+				t.Errorf("expected target %q; got %q", tt.target, tab.Target)
+			}
+			if r, c := tab.X.Dims(); r != len(tt.x) || c != len(tt.names) {
+				t.Fatalf("expected X to be %dx%d; got %dx%d", len(tt.x), len(tt.names), r, c)
+			}
+			for i, row := range tt.x {
+				for j, v := range row {
+					if got := tab.X.At(i, j); got != v {
+						t.Errorf("expected X[%d, %d] to be %v; got %v", i, j, v, got)
+					}
+				}
+			}
+			for i, v := range tt.y {
+				if got := tab.Y.At(i, 0); got != v {
+					t.Errorf("expected y[%d] to be %v; got %v", i, v, got)
+				}
+			}
+		})
+	}
+}
+
+func TestReadCSVErrors(t *testing.T) {
+	tc := []struct {
+		name string
+		in   string
+		opts CSVOptions
+		msg  string
+	}{
+		{"empty", "", CSVOptions{}, "no records"},
+		{"unknown target", "a,b\n1,2\n", CSVOptions{Header: true, Target: "c"}, `unknown column "c"`},
+		{"bad float", "a,b\n1,2\n3,x\n", CSVOptions{Header: true}, "line 3, column 2 (b)"},
+		{"missing value", "a,b\nNA,2\n", CSVOptions{Header: true, Missing: []string{"NA"}}, "missing value at line 2, column 1 (a)"},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadCSV(strings.NewReader(tt.in), tt.opts)
+			if err == nil {
+				t.Fatalf("expected error containing %q; got nil", tt.msg)
+			}
+			if !strings.Contains(err.Error(), tt.msg) {
+				t.Errorf("expected error containing %q; got %v", tt.msg, err)
+			}
+		})
+	}
+}
+
+func TestReadCSVMissing(t *testing.T) {
+	// Columns are skewed, so that their means and medians differ.
+	const data = "a,b,y\n1,NA,10\n3,4,\n,8,30\n5,6,40\n12,1,50\n"
+
+	tc := []struct {
+		name  string
+		opts  CSVOptions
+		names []string
+		x     [][]float64
+		mask  [][]float64
+		y     []float64
+	}{
+		{
+			name:  "drop rows",
+			opts:  CSVOptions{Impute: DropRows},
+			names: []string{"a", "b"},
+			x:     [][]float64{{5, 6}, {12, 1}},
+			mask:  [][]float64{{0, 0}, {0, 0}},
+			y:     []float64{40, 50},
+		},
+		{
+			name:  "impute mean",
+			opts:  CSVOptions{Impute: ImputeMean},
+			names: []string{"a", "b"},
+			x:     [][]float64{{1, 5}, {6, 8}, {5, 6}, {12, 1}},
+			mask:  [][]float64{{0, 1}, {1, 0}, {0, 0}, {0, 0}},
+			y:     []float64{10, 30, 40, 50},
+		},
+		{
+			name:  "impute median",
+			opts:  CSVOptions{Impute: ImputeMedian},
+			names: []string{"a", "b"},
+			x:     [][]float64{{1, 6}, {5, 8}, {5, 6}, {12, 1}},
+			mask:  [][]float64{{0, 1}, {1, 0}, {0, 0}, {0, 0}},
+			y:     []float64{10, 30, 40, 50},
+		},
+		{
+			name:  "impute constant with indicators",
+			opts:  CSVOptions{Impute: ImputeConstant, Fill: -1, Indicator: true},
+			names: []string{"a", "b", "a_missing", "b_missing"},
+			x:     [][]float64{{1, -1, 0, 1}, {-1, 8, 1, 0}, {5, 6, 0, 0}, {12, 1, 0, 0}},
+			mask:  [][]float64{{0, 1}, {1, 0}, {0, 0}, {0, 0}},
+			y:     []float64{10, 30, 40, 50},
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := tt.opts
+			opts.Header = true
+			opts.Target = "y"
+			opts.Missing = []string{"", "NA"}
+			tab, err := ReadCSV(strings.NewReader(data), opts)
+			if err != nil {
+				t.Fatalf("could not read csv: %v", err)
+			}
+			if strings.Join(tab.Names, ",") != strings.Join(tt.names, ",") {
+				t.Errorf("expected names %v; got %v", tt.names, tab.Names)
+			}
+			if r, _ := tab.X.Dims(); r != len(tt.x) {
+				t.Fatalf("expected %d rows; got %d", len(tt.x), r)
+			}
+			for i := range tt.x {
+				for j, v := range tt.x[i] {
+					if got := tab.X.At(i, j); got != v {
+						t.Errorf("expected X[%d, %d] to be %v; got %v", i, j, v, got)
+					}
+				}
+				for j, v := range tt.mask[i] {
+					if got := tab.Mask.At(i, j); got != v {
+						t.Errorf("expected mask[%d, %d] to be %v; got %v", i, j, v, got)
+					}
+				}
+				if got := tab.Y.At(i, 0); got != tt.y[i] {
+					t.Errorf("expected y[%d] to be %v; got %v", i, tt.y[i], got)
+				}
+			}
+		})
+	}
+}
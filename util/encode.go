@@ -0,0 +1,126 @@
+package util
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/campoy/mat"
+	"github.com/pkg/errors"
+)
+
+// An Encoding defines how an Encoder turns categories into columns.
+type Encoding int
+
+const (
+	// OneHot encodes each category as its own column set to 1.
+	OneHot Encoding = iota
+	// Ordinal encodes each category as its position in the vocabulary.
+	Ordinal
+)
+
+// An UnseenPolicy defines how an Encoder handles categories it was not fitted on.
+type UnseenPolicy int
+
+const (
+	// UnseenError fails with an UnseenCategoryError.
+	UnseenError UnseenPolicy = iota
+	// UnseenIgnore encodes unseen categories as all zeros for OneHot
+	// and as -1 for Ordinal.
+	UnseenIgnore
+)
+
+// An UnseenCategoryError is returned when encoding a category
+// the Encoder was not fitted on.
+type UnseenCategoryError struct {
+	Category string
+}
+
+func (e UnseenCategoryError) Error() string {
+	return fmt.Sprintf("unseen category %q", e.Category)
+}
+
+// An Encoder turns string values into numeric columns. Its exported fields
+// hold the fitted mapping, so it can be stored and reused on new data.
+type Encoder struct {
+	Encoding Encoding
+	Unseen   UnseenPolicy
+	// Categories is the vocabulary learned by Fit. When set by hand it must
+	// be sorted, since categories are looked up with a binary search, and
+	// Encode fails when it is not.
+	Categories []string
+}
+
+// Fit learns the vocabulary of the given values, replacing any previous one.
+func (e *Encoder) Fit(values []string) {
+	seen := make(map[string]bool)
+	e.Categories = nil
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			e.Categories = append(e.Categories, v)
+		}
+	}
+	sort.Strings(e.Categories)
+}
+
+// Width returns the number of columns each encoded value takes.
+func (e *Encoder) Width() int {
+	if e.Encoding == Ordinal {
+		return 1
+	}
+	return len(e.Categories)
+}
+
+// Names returns the names of the columns produced when encoding
+// the column with the given name, such as "color=red".
+func (e *Encoder) Names(name string) []string {
+	if e.Encoding == Ordinal {
+		return []string{name}
+	}
+	names := make([]string, len(e.Categories))
+	for i, c := range e.Categories {
+		names[i] = name + "=" + c
+	}
+	return names
+}
+
+// Encode writes the encoding of value into dst, which must have Width elements.
+func (e *Encoder) Encode(value string, dst []float64) error {
+	pos := sort.SearchStrings(e.Categories, value)
+	ok := pos < len(e.Categories) && e.Categories[pos] == value
+	// A value found is in its right column even if the categories are not
+	// sorted, so their order only needs to be checked when it is missing.
+	if !ok && !sort.StringsAreSorted(e.Categories) {
+		return errors.New("categories are not sorted")
+	}
+	if !ok && e.Unseen == UnseenError {
+		return UnseenCategoryError{value}
+	}
+
+	if e.Encoding == Ordinal {
+		dst[0] = -1
+		if ok {
+			dst[0] = float64(pos)
+		}
+		return nil
+	}
+	for i := range dst {
+		dst[i] = 0
+	}
+	if ok {
+		dst[pos] = 1
+	}
+	return nil
+}
+
+// Transform encodes the given values into a matrix with a row per value.
+func (e *Encoder) Transform(values []string) (mat.Matrix, error) {
+	w := e.Width()
+	data := make([]float64, len(values)*w)
+	for i, v := range values {
+		if err := e.Encode(v, data[i*w:(i+1)*w]); err != nil {
+			return mat.Matrix{}, err
+		}
+	}
+	return mat.FromSlice(len(values), w, data), nil
+}
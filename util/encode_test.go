@@ -0,0 +1,151 @@
+package util
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEncoder(t *testing.T) {
+	values := []string{"red", "green", "red", "blue"}
+
+	tc := []struct {
+		name   string
+		enc    Encoder
+		values []string
+		names  []string
+		want   [][]float64
+		err    bool
+	}{
+		{
+			name:   "one hot",
+			enc:    Encoder{Encoding: OneHot},
+			values: []string{"green", "red"},
+			names:  []string{"color=blue", "color=green", "color=red"},
+			want:   [][]float64{{0, 1, 0}, {0, 0, 1}},
+		},
+		{
+			name:   "ordinal",
+			enc:    Encoder{Encoding: Ordinal},
+			values: []string{"blue", "red"},
+			names:  []string{"color"},
+			want:   [][]float64{{0}, {2}},
+		},
+		{
+			name:   "one hot ignoring unseen",
+			enc:    Encoder{Encoding: OneHot, Unseen: UnseenIgnore},
+			values: []string{"pink"},
+			names:  []string{"color=blue", "color=green", "color=red"},
+			want:   [][]float64{{0, 0, 0}},
+		},
+		{
+			name:   "ordinal ignoring unseen",
+			enc:    Encoder{Encoding: Ordinal, Unseen: UnseenIgnore},
+			values: []string{"pink"},
+			names:  []string{"color"},
+			want:   [][]float64{{-1}},
+		},
+		{
+			name:   "unseen error",
+			enc:    Encoder{Encoding: OneHot},
+			values: []string{"pink"},
+			err:    true,
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.enc.Fit(values)
+			m, err := tt.enc.Transform(tt.values)
+			if tt.err {
+				if _, ok := err.(UnseenCategoryError); !ok {
+					t.Fatalf("expected UnseenCategoryError; got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("could not transform: %v", err)
+			}
+			if got := strings.Join(tt.enc.Names("color"), ","); got != strings.Join(tt.names, ",") {
+				t.Errorf("expected names %v; got %v", tt.names, got)
+			}
+			for i, row := range tt.want {
+				for j, v := range row {
+					if got := m.At(i, j); got != v {
+						t.Errorf("expected [%d, %d] to be %v; got %v", i, j, v, got)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestEncoderEditedCategories(t *testing.T) {
+	e := Encoder{Encoding: Ordinal}
+	e.Fit([]string{"red", "green"})
+	dst := make([]float64, 1)
+	if err := e.Encode("red", dst); err != nil || dst[0] != 1 {
+		t.Fatalf("expected red to be 1; got %v, %v", dst[0], err)
+	}
+
+	e.Categories = []string{"blue", "green", "red"}
+	if err := e.Encode("red", dst); err != nil || dst[0] != 2 {
+		t.Errorf("expected red to be 2 after editing categories; got %v, %v", dst[0], err)
+	}
+	if err := e.Encode("blue", dst); err != nil || dst[0] != 0 {
+		t.Errorf("expected blue to be 0 after editing categories; got %v, %v", dst[0], err)
+	}
+
+	// Unsorted categories would make a binary search miss known values.
+	e.Categories = []string{"red", "green", "blue"}
+	e.Unseen = UnseenIgnore
+	if err := e.Encode("blue", dst); err == nil {
+		t.Errorf("expected error encoding with unsorted categories; got %v", dst[0])
+	}
+}
+
+func TestReadCSVCategorical(t *testing.T) {
+	const train = "city,size,sold\nparis,10,yes\nrome,20,no\nparis,30,yes\n"
+	city := &Encoder{Encoding: OneHot}
+	tab, err := ReadCSV(strings.NewReader(train), CSVOptions{
+		Header: true,
+		Target: "sold",
+		Categorical: map[string]*Encoder{
+			"city": city,
+			"sold": {Encoding: Ordinal},
+		},
+	})
+	if err != nil {
+		t.Fatalf("could not read csv: %v", err)
+	}
+	if len(city.Categories) != 0 {
+		t.Errorf("expected the given encoder not to be fitted; got categories %v", city.Categories)
+	}
+	if got := strings.Join(tab.Encoders["city"].Categories, ","); got != "paris,rome" {
+		t.Errorf("expected fitted categories paris,rome; got %s", got)
+	}
+	if got := strings.Join(tab.Names, ","); got != "city=paris,city=rome,size" {
+		t.Errorf("unexpected names %s", got)
+	}
+	want := [][]float64{{1, 0, 10}, {0, 1, 20}, {1, 0, 30}}
+	for i, row := range want {
+		for j, v := range row {
+			if got := tab.X.At(i, j); got != v {
+				t.Errorf("expected X[%d, %d] to be %v; got %v", i, j, v, got)
+			}
+		}
+	}
+	for i, v := range []float64{1, 0, 1} {
+		if got := tab.Y.At(i, 0); got != v {
+			t.Errorf("expected y[%d] to be %v; got %v", i, v, got)
+		}
+	}
+
+	const test = "city,size\nrome,40\nmadrid,50\n"
+	_, err = ReadCSV(strings.NewReader(test), CSVOptions{
+		Header:      true,
+		Categorical: map[string]*Encoder{"city": tab.Encoders["city"]},
+	})
+	if err == nil || !strings.Contains(err.Error(), `unseen category "madrid"`) {
+		t.Errorf("expected unseen category error; got %v", err)
+	}
+}
@@ -0,0 +1,147 @@
+package util
+
+import (
+	"math"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// A MissingStrategy defines how missing values found while parsing
+// a CSV file are handled.
+type MissingStrategy int
+
+const (
+	// MissingError fails on the first missing value.
+	MissingError MissingStrategy = iota
+	// DropRows drops every row with a missing value.
+	DropRows
+	// ImputeMean replaces missing values with the mean of their column.
+	ImputeMean
+	// ImputeMedian replaces missing values with the median of their column.
+	ImputeMedian
+	// ImputeConstant replaces missing values with CSVOptions.Fill.
+	ImputeConstant
+)
+
+// keptRows returns the indices of the rows to keep out of the row-major
+// features xs with cols columns and the targets ys, where missing values
+// are NaN. Rows with a missing target are always dropped, since there is
+// nothing to learn from them, and so are rows with missing features when
+// the strategy is DropRows.
+func keptRows(xs, ys []float64, rows, cols int, opts CSVOptions) []int {
+	var keep []int
+	for i := 0; i < rows; i++ {
+		if len(ys) > 0 && math.IsNaN(ys[i]) {
+			continue
+		}
+		if opts.Impute == DropRows && hasNaN(xs[i*cols:(i+1)*cols]) {
+			continue
+		}
+		keep = append(keep, i)
+	}
+	return keep
+}
+
+// takeRows returns the rows with the given indices out of the row-major xs.
+func takeRows(xs []float64, cols int, idx []int) []float64 {
+	res := make([]float64, 0, len(idx)*cols)
+	for _, i := range idx {
+		res = append(res, xs[i*cols:(i+1)*cols]...)
+	}
+	return res
+}
+
+// impute replaces in place the missing values in the row-major features xs,
+// whose columns are named by names, following the strategy in opts.
+// It returns the mask of the values that were missing in xs and the value
+// used to fill each column.
+func impute(xs []float64, names []string, opts CSVOptions) (mask, fills []float64, err error) {
+	cols := len(names)
+	mask = make([]float64, len(xs))
+	for i, v := range xs {
+		if math.IsNaN(v) {
+			mask[i] = 1
+		}
+	}
+
+	fills = make([]float64, cols)
+	for j := range fills {
+		col := make([]float64, 0, len(xs)/cols)
+		for i := j; i < len(xs); i += cols {
+			if !math.IsNaN(xs[i]) {
+				col = append(col, xs[i])
+			}
+		}
+
+		switch opts.Impute {
+		case DropRows:
+			fills[j] = math.NaN()
+			continue
+		case ImputeConstant:
+			fills[j] = opts.Fill
+		case ImputeMean:
+			fills[j] = mean(col)
+		case ImputeMedian:
+			fills[j] = median(col)
+		default:
+			return nil, nil, errors.Errorf("unknown missing strategy %d", opts.Impute)
+		}
+		if math.IsNaN(fills[j]) {
+			return nil, nil, errors.Errorf("column %q has no values to impute from", names[j])
+		}
+		for i := j; i < len(xs); i += cols {
+			if math.IsNaN(xs[i]) {
+				xs[i] = fills[j]
+			}
+		}
+	}
+	return mask, fills, nil
+}
+
+// indicatorColumns returns the indices of the columns with missing values
+// according to the row-major mask.
+func indicatorColumns(mask []float64, cols int) []int {
+	var ind []int
+	for j := 0; j < cols; j++ {
+		for i := j; i < len(mask); i += cols {
+			if mask[i] == 1 {
+				ind = append(ind, j)
+				break
+			}
+		}
+	}
+	return ind
+}
+
+func hasNaN(xs []float64) bool {
+	for _, x := range xs {
+		if math.IsNaN(x) {
+			return true
+		}
+	}
+	return false
+}
+
+func mean(xs []float64) float64 {
+	if len(xs) == 0 {
+		return math.NaN()
+	}
+	sum := 0.0
+	for _, x := range xs {
+		sum += x
+	}
+	return sum / float64(len(xs))
+}
+
+func median(xs []float64) float64 {
+	if len(xs) == 0 {
+		return math.NaN()
+	}
+	s := append([]float64(nil), xs...)
+	sort.Float64s(s)
+	if n := len(s); n%2 == 0 {
+		return (s[n/2-1] + s[n/2]) / 2
+	}
+	return s[len(s)/2]
+}
@@ -0,0 +1,136 @@
+package util
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/campoy/tools/imgcat"
+	"github.com/pkg/errors"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/vg"
+)
+
+// A Sink receives rendered plots, so the code producing them does not
+// need to know whether they are shown in a terminal or stored somewhere.
+type Sink interface {
+	WritePlot(p *plot.Plot, width, height vg.Length) error
+}
+
+// TerminalSink returns a Sink that writes plots as PNG images to the given
+// encoder. Plots are discarded when enc is nil, which happens when the
+// terminal does not support images.
+func TerminalSink(enc *imgcat.Encoder) Sink { return terminalSink{enc} }
+
+type terminalSink struct{ enc *imgcat.Encoder }
+
+func (s terminalSink) WritePlot(p *plot.Plot, width, height vg.Length) error {
+	if s.enc == nil {
+		return nil
+	}
+	wt, err := p.WriterTo(width, height, "png")
+	if err != nil {
+		return errors.Wrap(err, "could not create writer from plot")
+	}
+	wc := s.enc.Writer()
+	if _, err := wt.WriteTo(wc); err != nil {
+		wc.Close()
+		return errors.Wrap(err, "could not write to imgcat")
+	}
+	return errors.Wrap(wc.Close(), "could not close imgcat")
+}
+
+// A DirSink saves every plot it receives as a new file in a directory,
+// named plot-001.png, plot-002.png, and so on. Numbering continues after
+// the plots already in the directory, so earlier runs are not overwritten.
+type DirSink struct {
+	Dir    string
+	Format string // One of png, svg or pdf.
+
+	n int
+}
+
+// NewDirSink returns a DirSink saving plots in the given format into dir,
+// which is created if it does not exist.
+func NewDirSink(dir, format string) (*DirSink, error) {
+	switch format {
+	case "png", "svg", "pdf":
+	default:
+		return nil, errors.Errorf("unsupported plot format %q", format)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, errors.Wrapf(err, "could not create %s", dir)
+	}
+	return &DirSink{Dir: dir, Format: format}, nil
+}
+
+// WritePlot saves p into the next numbered file.
+func (s *DirSink) WritePlot(p *plot.Plot, width, height vg.Length) error {
+	if s.n == 0 {
+		n, err := lastPlot(s.Dir)
+		if err != nil {
+			return err
+		}
+		s.n = n
+	}
+	s.n++
+	path := filepath.Join(s.Dir, fmt.Sprintf("plot-%03d.%s", s.n, s.Format))
+	return errors.Wrapf(p.Save(width, height, path), "could not save %s", path)
+}
+
+// lastPlot returns the highest number of the plots saved in dir by a DirSink
+// in any format, or 0 if there are none.
+func lastPlot(dir string) (int, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "plot-*.*"))
+	if err != nil {
+		return 0, errors.Wrapf(err, "could not list plots in %s", dir)
+	}
+	last := 0
+	for _, path := range paths {
+		var n int
+		if _, err := fmt.Sscanf(filepath.Base(path), "plot-%d.", &n); err == nil && n > last {
+			last = n
+		}
+	}
+	return last, nil
+}
+
+// A BufferSink keeps the plots it receives in memory, which is useful in tests.
+type BufferSink struct {
+	Format string // Format used to render plots, png by default.
+	Plots  [][]byte
+}
+
+// WritePlot renders p and appends it to Plots.
+func (s *BufferSink) WritePlot(p *plot.Plot, width, height vg.Length) error {
+	format := s.Format
+	if format == "" {
+		format = "png"
+	}
+	wt, err := p.WriterTo(width, height, format)
+	if err != nil {
+		return errors.Wrap(err, "could not create writer from plot")
+	}
+	var buf bytes.Buffer
+	if _, err := wt.WriteTo(&buf); err != nil {
+		return errors.Wrap(err, "could not render plot")
+	}
+	s.Plots = append(s.Plots, buf.Bytes())
+	return nil
+}
+
+// NewSink returns a DirSink saving PNG files into dir when it is not empty,
+// and a TerminalSink writing to the standard output otherwise. This allows
+// examples to show plots in a terminal or store them when run headless.
+func NewSink(dir string) (Sink, error) {
+	if dir != "" {
+		return NewDirSink(dir, "png")
+	}
+	enc, err := imgcat.NewEncoder(os.Stdout,
+		imgcat.Width(imgcat.Cells(100)), imgcat.Inline(true))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "images will not be shown: %v\n", err)
+	}
+	return TerminalSink(enc), nil
+}
@@ -0,0 +1,63 @@
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gonum.org/v1/plot"
+)
+
+func TestBufferSink(t *testing.T) {
+	p, err := plot.New()
+	if err != nil {
+		t.Fatalf("could not create plot: %v", err)
+	}
+	var s BufferSink
+	for i := 0; i < 2; i++ {
+		if err := s.WritePlot(p, 100, 100); err != nil {
+			t.Fatalf("could not write plot: %v", err)
+		}
+	}
+	if len(s.Plots) != 2 || len(s.Plots[0]) == 0 {
+		t.Errorf("expected two rendered plots; got %d", len(s.Plots))
+	}
+}
+
+func TestDirSink(t *testing.T) {
+	if _, err := NewDirSink(t.TempDir(), "gif"); err == nil {
+		t.Errorf("expected error for unsupported format")
+	}
+
+	dir := filepath.Join(t.TempDir(), "plots")
+	s, err := NewDirSink(dir, "svg")
+	if err != nil {
+		t.Fatalf("could not create sink: %v", err)
+	}
+	p, err := plot.New()
+	if err != nil {
+		t.Fatalf("could not create plot: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := s.WritePlot(p, 100, 100); err != nil {
+			t.Fatalf("could not write plot: %v", err)
+		}
+	}
+	for _, name := range []string{"plot-001.svg", "plot-002.svg"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("expected %s to exist: %v", name, err)
+		}
+	}
+
+	// A new sink on the same directory does not overwrite the plots.
+	s, err = NewDirSink(dir, "png")
+	if err != nil {
+		t.Fatalf("could not create sink: %v", err)
+	}
+	if err := s.WritePlot(p, 100, 100); err != nil {
+		t.Fatalf("could not write plot: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "plot-003.png")); err != nil {
+		t.Errorf("expected plot-003.png to exist: %v", err)
+	}
+}
@@ -1,61 +1,37 @@
 package util
 
 import (
-	"encoding/csv"
-	"log"
-	"os"
-	"strconv"
+	"fmt"
 
 	"github.com/campoy/mat"
 	"github.com/campoy/tools/imgcat"
-	"github.com/pkg/errors"
 	"gonum.org/v1/plot"
 	"gonum.org/v1/plot/vg"
 )
 
 // ParseMatrix parses a CSV encoded file and returns a matrix of float64 values.
 func ParseMatrix(path string) (mat.Matrix, error) {
-	f, err := os.Open(path)
+	t, err := ParseCSV(path, CSVOptions{})
 	if err != nil {
-		return mat.Matrix{}, errors.Wrapf(err, "could not read %s", path)
+		return mat.Matrix{}, err
 	}
-	defer f.Close()
-
-	recs, err := csv.NewReader(f).ReadAll()
-	if err != nil {
-		return mat.Matrix{}, errors.Wrap(err, "could not read records")
-	}
-
-	data := make([]float64, 0, len(recs)*len(recs[0]))
-
-	for _, rec := range recs {
-		for _, v := range rec {
-			x, err := strconv.ParseFloat(v, 64)
-			if err != nil {
-				return mat.Matrix{}, errors.Wrapf(err, "could not parse float %s", v)
-			}
-			data = append(data, x)
-		}
-	}
-
-	return mat.FromSlice(len(recs), len(recs[0]), data), nil
+	return t.X, nil
 }
 
-// PrintPlot prints a plot to the given encoder.
-func PrintPlot(enc *imgcat.Encoder, p *plot.Plot, width, height vg.Length) {
-	if enc == nil {
-		return
-	}
+// PrintPlot prints a plot to the given encoder as a PNG image.
+// It does nothing if enc is nil.
+func PrintPlot(enc *imgcat.Encoder, p *plot.Plot, width, height vg.Length) error {
+	return TerminalSink(enc).WritePlot(p, width, height)
+}
 
-	wc := enc.Writer()
-	wt, err := p.WriterTo(width, height, "png")
-	if err != nil {
-		log.Fatalf("could not create writer from plot: %v", err)
-	}
-	if _, err := wt.WriteTo(wc); err != nil {
-		log.Fatalf("could not write to imgcat: %v", err)
-	}
-	if err := wc.Close(); err != nil {
-		log.Fatalf("could not close imgcat: %v", err)
+// PrintMatrix prints the values of m, row by row, after the given name.
+func PrintMatrix(name string, m Matrix) {
+	fmt.Printf("%s:\n", name)
+	r, c := m.Dims()
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			fmt.Printf(" %12.4f", m.At(i, j))
+		}
+		fmt.Println()
 	}
 }
@@ -0,0 +1,190 @@
+package util
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/csv"
+	"io"
+	"math"
+	"os"
+	"strconv"
+
+	"github.com/campoy/mat"
+	"github.com/pkg/errors"
+)
+
+// A Matrix is the read-only view of a matrix shared by github.com/campoy/mat
+// and gonum.org/v1/gonum/mat, so functions accepting it work with both.
+type Matrix interface {
+	Dims() (r, c int)
+	At(i, j int) float64
+}
+
+// SaveCSV writes the matrix m to the file at path, see WriteCSV.
+func SaveCSV(path string, m Matrix, names []string, prec int) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return errors.Wrapf(err, "could not create %s", path)
+	}
+	if err := WriteCSV(f, m, names, prec); err != nil {
+		f.Close()
+		return err
+	}
+	return errors.Wrapf(f.Close(), "could not close %s", path)
+}
+
+// WriteCSV writes the rows of m to w as CSV records, preceded by a header
+// with the given names unless it is empty. Values are written with prec
+// decimals, or with as many as needed to represent them exactly when prec
+// is negative.
+func WriteCSV(w io.Writer, m Matrix, names []string, prec int) error {
+	r, c := m.Dims()
+	if len(names) > 0 && len(names) != c {
+		return errors.Errorf("got %d names for %d columns", len(names), c)
+	}
+
+	cw := csv.NewWriter(w)
+	if len(names) > 0 {
+		if err := cw.Write(names); err != nil {
+			return errors.Wrap(err, "could not write header")
+		}
+	}
+	rec := make([]string, c)
+	for i := 0; i < r; i++ {
+		for j := range rec {
+			rec[j] = strconv.FormatFloat(m.At(i, j), 'f', prec, 64)
+		}
+		if err := cw.Write(rec); err != nil {
+			return errors.Wrapf(err, "could not write row %d", i)
+		}
+	}
+	cw.Flush()
+	return errors.Wrap(cw.Error(), "could not write records")
+}
+
+// A DType is the type used to store each value in the binary format.
+type DType uint8
+
+// Values can be stored in 8 or 4 bytes, the latter losing precision.
+const (
+	Float64 DType = 8
+	Float32 DType = 4
+)
+
+// binaryMagic identifies files written by WriteBinary.
+const binaryMagic = "GOML"
+
+// maxBinaryValues is the largest number of values ReadBinary accepts, 16GiB
+// of float64, to reject corrupt headers.
+const maxBinaryValues = 1 << 31
+
+// binaryChunk is the number of values ReadBinary reads at a time.
+const binaryChunk = 1 << 16
+
+// binaryHeader precedes the little-endian, row-major values of a matrix
+// written by WriteBinary.
+type binaryHeader struct {
+	Magic      [4]byte
+	Version    uint8
+	DType      DType
+	_          uint16
+	Rows, Cols uint64
+}
+
+// SaveBinary writes the matrix m to the file at path, see WriteBinary.
+func SaveBinary(path string, m Matrix, dtype DType) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return errors.Wrapf(err, "could not create %s", path)
+	}
+	if err := WriteBinary(f, m, dtype); err != nil {
+		f.Close()
+		return err
+	}
+	return errors.Wrapf(f.Close(), "could not close %s", path)
+}
+
+// WriteBinary writes m to w in a compact binary format that records its
+// shape and the type of its values, which can be read back by ReadBinary.
+func WriteBinary(w io.Writer, m Matrix, dtype DType) error {
+	if dtype != Float64 && dtype != Float32 {
+		return errors.Errorf("unknown dtype %d", dtype)
+	}
+	r, c := m.Dims()
+	h := binaryHeader{Version: 1, DType: dtype, Rows: uint64(r), Cols: uint64(c)}
+	copy(h.Magic[:], binaryMagic)
+
+	bw := bufio.NewWriter(w)
+	if err := binary.Write(bw, binary.LittleEndian, h); err != nil {
+		return errors.Wrap(err, "could not write header")
+	}
+	buf := make([]byte, int(dtype)*c)
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			if dtype == Float64 {
+				binary.LittleEndian.PutUint64(buf[8*j:], math.Float64bits(m.At(i, j)))
+			} else {
+				binary.LittleEndian.PutUint32(buf[4*j:], math.Float32bits(float32(m.At(i, j))))
+			}
+		}
+		if _, err := bw.Write(buf); err != nil {
+			return errors.Wrapf(err, "could not write row %d", i)
+		}
+	}
+	return errors.Wrap(bw.Flush(), "could not write values")
+}
+
+// LoadBinary reads the matrix in the file at path, see ReadBinary.
+func LoadBinary(path string) (mat.Matrix, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return mat.Matrix{}, errors.Wrapf(err, "could not read %s", path)
+	}
+	defer f.Close()
+
+	m, err := ReadBinary(bufio.NewReader(f))
+	return m, errors.Wrapf(err, "could not parse %s", path)
+}
+
+// ReadBinary reads a matrix written by WriteBinary from r.
+func ReadBinary(r io.Reader) (mat.Matrix, error) {
+	var h binaryHeader
+	if err := binary.Read(r, binary.LittleEndian, &h); err != nil {
+		return mat.Matrix{}, errors.Wrap(err, "could not read header")
+	}
+	if string(h.Magic[:]) != binaryMagic {
+		return mat.Matrix{}, errors.New("wrong magic number in header")
+	}
+	if h.Version != 1 {
+		return mat.Matrix{}, errors.Errorf("unknown version %d", h.Version)
+	}
+
+	if h.DType != Float64 && h.DType != Float32 {
+		return mat.Matrix{}, errors.Errorf("unknown dtype %d", h.DType)
+	}
+	if h.Rows > maxBinaryValues || h.Cols > maxBinaryValues ||
+		(h.Cols > 0 && h.Rows > maxBinaryValues/h.Cols) {
+		return mat.Matrix{}, errors.Errorf("matrix of %dx%d is too large", h.Rows, h.Cols)
+	}
+
+	// Values are read in chunks, so a corrupt header cannot allocate
+	// more memory than the stream actually holds.
+	n := int(h.Rows * h.Cols)
+	data := make([]float64, 0, min(n, binaryChunk))
+	buf := make([]byte, int(h.DType)*min(n, binaryChunk))
+	for len(data) < n {
+		k := min(n-len(data), binaryChunk)
+		b := buf[:int(h.DType)*k]
+		if _, err := io.ReadFull(r, b); err != nil {
+			return mat.Matrix{}, errors.Wrap(err, "could not read values")
+		}
+		for i := 0; i < k; i++ {
+			if h.DType == Float64 {
+				data = append(data, math.Float64frombits(binary.LittleEndian.Uint64(b[8*i:])))
+			} else {
+				data = append(data, float64(math.Float32frombits(binary.LittleEndian.Uint32(b[4*i:]))))
+			}
+		}
+	}
+	return mat.FromSlice(int(h.Rows), int(h.Cols), data), nil
+}
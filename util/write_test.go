@@ -0,0 +1,107 @@
+package util
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+
+	"github.com/campoy/mat"
+)
+
+func TestWriteCSV(t *testing.T) {
+	m := mat.FromSlice(2, 2, []float64{1, 0.5, -2, 1.0 / 3})
+
+	tc := []struct {
+		name  string
+		names []string
+		prec  int
+		want  string
+	}{
+		{"exact", nil, -1, "1,0.5\n-2,0.3333333333333333\n"},
+		{"two decimals with header", []string{"a", "b"}, 2, "a,b\n1.00,0.50\n-2.00,0.33\n"},
+	}
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WriteCSV(&buf, m, tt.names, tt.prec); err != nil {
+				t.Fatalf("could not write: %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("expected %q; got %q", tt.want, buf.String())
+			}
+		})
+	}
+
+	tab, err := ReadCSV(strings.NewReader("a,b\n1,0.5\n"), CSVOptions{Header: true})
+	if err != nil {
+		t.Fatalf("could not read: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, tab.X, tab.Names, -1); err != nil {
+		t.Fatalf("could not write: %v", err)
+	}
+	if want := "a,b\n1,0.5\n"; buf.String() != want {
+		t.Errorf("expected round trip to give %q; got %q", want, buf.String())
+	}
+}
+
+func TestBinary(t *testing.T) {
+	m := mat.FromSlice(2, 3, []float64{1, 2.5, -3, 0, 1e10, 0.125})
+
+	for _, dtype := range []DType{Float64, Float32} {
+		var buf bytes.Buffer
+		if err := WriteBinary(&buf, m, dtype); err != nil {
+			t.Fatalf("could not write: %v", err)
+		}
+		if want := 24 + 6*int(dtype); buf.Len() != want {
+			t.Errorf("expected %d bytes; got %d", want, buf.Len())
+		}
+
+		got, err := ReadBinary(&buf)
+		if err != nil {
+			t.Fatalf("could not read: %v", err)
+		}
+		if r, c := got.Dims(); r != 2 || c != 3 {
+			t.Fatalf("expected 2x3 matrix; got %dx%d", r, c)
+		}
+		for i := 0; i < 2; i++ {
+			for j := 0; j < 3; j++ {
+				if got.At(i, j) != m.At(i, j) {
+					t.Errorf("dtype %d: expected [%d, %d] to be %v; got %v", dtype, i, j, m.At(i, j), got.At(i, j))
+				}
+			}
+		}
+	}
+
+	if _, err := ReadBinary(strings.NewReader("NOPE0000000000000000000000")); err == nil {
+		t.Errorf("expected error reading bad magic number")
+	}
+}
+
+func TestReadBinaryCorrupt(t *testing.T) {
+	tc := []struct {
+		name       string
+		rows, cols uint64
+		msg        string
+	}{
+		{"negative as int", 1 << 63, 1, "too large"},
+		{"overflowing product", 1 << 31, 1 << 31, "too large"},
+		{"above the bound", 1 << 20, 1 << 12, "too large"},
+		{"truncated values", 1 << 20, 1 << 10, "could not read values"},
+	}
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			h := binaryHeader{Version: 1, DType: Float64, Rows: tt.rows, Cols: tt.cols}
+			copy(h.Magic[:], binaryMagic)
+			var buf bytes.Buffer
+			binary.Write(&buf, binary.LittleEndian, h)
+			buf.Write(make([]byte, 80))
+
+			_, err := ReadBinary(&buf)
+			if err == nil || !strings.Contains(err.Error(), tt.msg) {
+				t.Errorf("expected error containing %q; got %v", tt.msg, err)
+			}
+		})
+	}
+}